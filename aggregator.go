@@ -0,0 +1,96 @@
+package ses
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// EventCounts are the counters of a single tag value (ie: a campaign)
+type EventCounts struct {
+	Sends      int64 `json:"sends"`
+	Failures   int64 `json:"failures"`
+	Deliveries int64 `json:"deliveries"`
+	Bounces    int64 `json:"bounces"`
+	Complaints int64 `json:"complaints"`
+	Rejects    int64 `json:"rejects"`
+}
+
+// StatsAggregator counts sends, bounces, complaints, etc. in memory, grouped by the
+// value of a message tag (ie: "campaign"). It is decoupled from CloudWatch, for
+// services that need immediate in-process counters. Messages without the tag are
+// counted under the empty string
+type StatsAggregator struct {
+	counts map[string]*EventCounts
+	mu     sync.Mutex
+	tag    string
+}
+
+// NewStatsAggregator will return an aggregator that groups by the tag name
+func NewStatsAggregator(tag string) *StatsAggregator {
+	return &StatsAggregator{tag: tag, counts: make(map[string]*EventCounts)}
+}
+
+// RecordSend will count a send attempt of a message with the tags (err is the send error)
+func (a *StatsAggregator) RecordSend(tags map[string]string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	counts := a.get(tags[a.tag])
+	if err != nil {
+		counts.Failures++
+		return
+	}
+	counts.Sends++
+}
+
+// RecordEvent will count a delivery, bounce, complaint or reject event
+func (a *StatsAggregator) RecordEvent(e *Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	counts := a.get(e.Mail.Tag(a.tag))
+	switch e.Type() {
+	case EventTypeDelivery:
+		counts.Deliveries++
+	case EventTypeBounce:
+		counts.Bounces++
+	case EventTypeComplaint:
+		counts.Complaints++
+	case EventTypeReject:
+		counts.Rejects++
+	}
+}
+
+// get returns the counters of the tag value (must hold the lock)
+func (a *StatsAggregator) get(value string) *EventCounts {
+	counts, ok := a.counts[value]
+	if !ok {
+		counts = &EventCounts{}
+		a.counts[value] = counts
+	}
+	return counts
+}
+
+// Snapshot returns a copy of the current counters
+func (a *StatsAggregator) Snapshot() map[string]EventCounts {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	snapshot := make(map[string]EventCounts, len(a.counts))
+	for value, counts := range a.counts {
+		snapshot[value] = *counts
+	}
+	return snapshot
+}
+
+// Reset will clear the counters and return the counters before the reset
+func (a *StatsAggregator) Reset() map[string]EventCounts {
+	snapshot := a.Snapshot()
+	a.mu.Lock()
+	a.counts = make(map[string]*EventCounts)
+	a.mu.Unlock()
+	return snapshot
+}
+
+// Export will write a snapshot of the counters as JSON
+func (a *StatsAggregator) Export(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a.Snapshot())
+}
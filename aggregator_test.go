@@ -0,0 +1,54 @@
+package ses
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestStatsAggregator will test the StatsAggregator
+func TestStatsAggregator(t *testing.T) {
+	a := NewStatsAggregator("campaign")
+	welcome := map[string]string{"campaign": "welcome"}
+	a.RecordSend(welcome, nil)
+	a.RecordSend(welcome, nil)
+	a.RecordSend(welcome, errors.New("throttled"))
+	a.RecordSend(nil, nil)
+
+	event := func(eventType string) *Event {
+		return &Event{EventType: eventType, Mail: EventMail{Tags: map[string][]string{"campaign": {"welcome"}}}}
+	}
+	a.RecordEvent(event(EventTypeDelivery))
+	a.RecordEvent(event(EventTypeBounce))
+	a.RecordEvent(event(EventTypeComplaint))
+	a.RecordEvent(event(EventTypeOpen))
+
+	snapshot := a.Snapshot()
+	expected := EventCounts{Sends: 2, Failures: 1, Deliveries: 1, Bounces: 1, Complaints: 1}
+	if snapshot["welcome"] != expected {
+		t.Errorf("expected %+v got %+v", expected, snapshot["welcome"])
+	}
+	if snapshot[""].Sends != 1 {
+		t.Errorf("expected untagged sends to be counted: %+v", snapshot)
+	}
+
+	var buf bytes.Buffer
+	if err := a.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var exported map[string]EventCounts
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	if exported["welcome"] != expected {
+		t.Errorf("Wrong export: %s", buf.String())
+	}
+
+	if before := a.Reset(); before["welcome"] != expected {
+		t.Errorf("expected the counters before the reset")
+	}
+	if len(a.Snapshot()) != 0 {
+		t.Errorf("expected no counters after the reset")
+	}
+}
@@ -0,0 +1,139 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ErrAttachmentRejected matches (errors.Is) every AttachmentRejectedError
+var ErrAttachmentRejected = errors.New("attachment rejected by the scanner")
+
+// Attachment is a decoded attachment of a raw message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// AttachmentScanner scans the attachments of raw messages before they are sent (ClamAV,
+// an S3 malware scanner...). Returning an error rejects the attachment and blocks the send
+type AttachmentScanner interface {
+	Scan(ctx context.Context, attachment *Attachment) error
+}
+
+// AttachmentScannerFunc is a function that implements AttachmentScanner
+type AttachmentScannerFunc func(ctx context.Context, attachment *Attachment) error
+
+// Scan scans the attachment
+func (f AttachmentScannerFunc) Scan(ctx context.Context, attachment *Attachment) error {
+	return f(ctx, attachment)
+}
+
+// AttachmentRejectedError is returned when the scanner rejects an attachment
+type AttachmentRejectedError struct {
+	Filename    string
+	ContentType string
+	Err         error
+}
+
+// Error returns the error message
+func (e *AttachmentRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s (%s): %s", ErrAttachmentRejected, e.Filename, e.ContentType, e.Err)
+}
+
+// Unwrap returns the scanner error
+func (e *AttachmentRejectedError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrAttachmentRejected
+func (e *AttachmentRejectedError) Is(target error) bool {
+	return target == ErrAttachmentRejected
+}
+
+// Attachments returns the decoded attachments of the raw message (the parts with an
+// attachment disposition or a file name, nested multiparts included)
+func (m *RawMessage) Attachments() ([]*Attachment, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(m.Data))
+	if err != nil {
+		return nil, err
+	}
+	return readAttachments(textproto.MIMEHeader(msg.Header), msg.Body)
+}
+
+// readAttachments walks a MIME entity and decodes its attachments
+func readAttachments(header textproto.MIMEHeader, body io.Reader) ([]*Attachment, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var attachments []*Attachment
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return attachments, nil
+			} else if err != nil {
+				return nil, err
+			}
+			partAttachments, err := readAttachments(part.Header, part)
+			if err != nil {
+				return nil, err
+			}
+			attachments = append(attachments, partAttachments...)
+		}
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if len(filename) == 0 {
+		filename = params["name"]
+	}
+	if disposition != "attachment" && len(filename) == 0 {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadAll(transferDecoder(header, body))
+	if err != nil {
+		return nil, err
+	}
+	return []*Attachment{{Filename: filename, ContentType: mediaType, Data: data}}, nil
+}
+
+// transferDecoder returns a reader decoding the Content-Transfer-Encoding of the body
+func transferDecoder(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// scanAttachments runs the scanner on every attachment of the raw message
+func scanAttachments(ctx context.Context, scanner AttachmentScanner, m *RawMessage) error {
+	attachments, err := m.Attachments()
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err = scanner.Scan(ctx, attachment); err != nil {
+			return &AttachmentRejectedError{Filename: attachment.Filename, ContentType: attachment.ContentType, Err: err}
+		}
+	}
+	return nil
+}
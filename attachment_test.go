@@ -0,0 +1,99 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// attachmentMessage is a raw message with a nested multipart and two attachments
+const attachmentMessage = "From: from@example.com\r\n" +
+	"To: to@example.com\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"hello\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"caf=C3=A9\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Disposition: attachment; filename=\"invoice.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0x\r\nLjQ=\r\n" +
+	"--outer--\r\n"
+
+// TestRawMessage_Attachments will test the method Attachments()
+func TestRawMessage_Attachments(t *testing.T) {
+	attachments, err := (&RawMessage{Data: []byte(attachmentMessage)}).Attachments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 attachments got %d", len(attachments))
+	}
+	if attachments[0].Filename != "notes.txt" || string(attachments[0].Data) != "café" {
+		t.Errorf("Wrong attachment: %+v", attachments[0])
+	}
+	if attachments[1].Filename != "invoice.pdf" || attachments[1].ContentType != "application/pdf" ||
+		string(attachments[1].Data) != "%PDF-1.4" {
+		t.Errorf("Wrong attachment: %+v", attachments[1])
+	}
+
+	if attachments, err = (&RawMessage{Data: []byte("From: a@example.com\r\n\r\nbody")}).Attachments(); err != nil || len(attachments) != 0 {
+		t.Errorf("expected no attachments got %v, %v", attachments, err)
+	}
+}
+
+// TestConfig_SendRawAttachmentScanner will test that a rejected attachment blocks the send
+func TestConfig_SendRawAttachmentScanner(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	infected := errors.New("Eicar-Test-Signature FOUND")
+	var scanned []string
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		AttachmentScanner: AttachmentScannerFunc(func(ctx context.Context, attachment *Attachment) error {
+			scanned = append(scanned, attachment.Filename)
+			if attachment.ContentType == "application/pdf" {
+				return infected
+			}
+			return nil
+		}),
+	}
+
+	_, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte(attachmentMessage)})
+	var rejected *AttachmentRejectedError
+	if !errors.As(err, &rejected) || rejected.Filename != "invoice.pdf" {
+		t.Fatalf("expected an AttachmentRejectedError got %v", err)
+	}
+	if !errors.Is(err, ErrAttachmentRejected) || !errors.Is(err, infected) {
+		t.Errorf("expected the error to match ErrAttachmentRejected and the scanner error: %v", err)
+	}
+	if calls != 0 || len(scanned) != 2 {
+		t.Errorf("expected no send after scanning 2 attachments: %d calls, scanned %v", calls, scanned)
+	}
+
+	if _, err = cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: a@example.com\r\n\r\nbody")}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the clean message to be sent")
+	}
+}
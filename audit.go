@@ -0,0 +1,173 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the audit records
+const (
+	actionSendEmail    = "SendEmail"
+	actionSendRawEmail = "SendRawEmail"
+)
+
+// Statuses recorded in the audit records
+const (
+	AuditStatusSent   = "sent"
+	AuditStatusFailed = "failed"
+)
+
+// AuditRecord is the record of a single send attempt. It is passed by value, so
+// sinks always receive their own copy
+type AuditRecord struct {
+	Time            time.Time `json:"time"`
+	Action          string    `json:"action"`
+	Region          string    `json:"region"`
+	From            string    `json:"from"`
+	RecipientHashes []string  `json:"recipient_hashes"`
+	MessageID       string    `json:"message_id,omitempty"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"` // Error code or type only (SES error bodies can contain addresses)
+}
+
+// AuditSink receives an AuditRecord for every send attempt. This is distinct from
+// debug logging and is meant for write-once (compliance) stores. Sinks must be safe
+// for concurrent use and handle their own write failures (a send is never failed
+// because it could not be audited)
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// audit will send the record of the attempt to the AuditSink (if set)
+func (c *Config) audit(action string, o *sendOptions, from string, to []string, result *SendResult, err error) {
+	if c.AuditSink == nil {
+		return
+	}
+	region, _ := c.target(o)
+	record := AuditRecord{
+		Time:            time.Now().UTC(),
+		Action:          action,
+		Region:          region,
+		From:            from,
+		RecipientHashes: redactRecipients(c.redactor(), to),
+		Status:          AuditStatusSent,
+	}
+	if err != nil {
+		record.Status = AuditStatusFailed
+		record.Error = auditError(err)
+	} else if result != nil {
+		record.MessageID = result.MessageID
+	}
+	c.AuditSink.Audit(record)
+}
+
+// auditErrors are the errors recorded by their text (they never contain message data)
+var auditErrors = []error{
+	ErrMissingFrom, ErrMissingRecipients, ErrMissingRawData, ErrInvalidTag,
+	ErrFromNotAllowed, ErrMisleadingFrom, ErrDMARCMisaligned, ErrUnverifiedSender,
+	ErrUnverifiedRecipient, ErrAttachmentRejected, ErrPGPMissingKey, ErrPGPFormattedMessage, ErrInvalidRawParam,
+	ErrEndpointMoved, ErrCertificatePinMismatch, context.Canceled, context.DeadlineExceeded,
+}
+
+// auditError will describe the error without its details: SES error responses (and
+// wrapped guard errors) can contain addresses or message content
+func auditError(err error) string {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return fmt.Sprintf("error code %d", responseErr.StatusCode)
+	}
+	for _, known := range auditErrors {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// recipients will combine the to, cc and bcc recipients
+func recipients(lists ...[]string) (all []string) {
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return
+}
+
+// rawAddresses will (best effort) read the From and recipient addresses from the raw message headers
+func rawAddresses(data []byte) (from string, to []string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	if address, err := mail.ParseAddress(msg.Header.Get("From")); err == nil {
+		from = address.Address
+	}
+	for _, key := range []string{"To", "Cc", "Bcc"} {
+		list, _ := msg.Header.AddressList(key)
+		for _, address := range list {
+			to = append(to, address.Address)
+		}
+	}
+	return
+}
+
+// JSONLinesAuditSink writes each AuditRecord as a line of JSON (append only)
+type JSONLinesAuditSink struct {
+	closer io.Closer
+	err    error
+	mu     sync.Mutex
+	w      io.Writer
+}
+
+// NewJSONLinesAuditSink will return a sink that writes to w
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenJSONLinesAuditFile will open (or create) the file in append-only mode
+func OpenJSONLinesAuditFile(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesAuditSink{w: f, closer: f}, nil
+}
+
+// Audit will write the record as a single line
+func (s *JSONLinesAuditSink) Audit(record AuditRecord) {
+	line, err := json.Marshal(record)
+	if err == nil {
+		line = append(line, '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		_, err = s.w.Write(line)
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the first write error (if any)
+func (s *JSONLinesAuditSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close will close the underlying file (if opened with OpenJSONLinesAuditFile)
+func (s *JSONLinesAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
@@ -0,0 +1,187 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// auditRecorder is an in-memory AuditSink for testing
+type auditRecorder struct {
+	records []AuditRecord
+}
+
+// Audit will keep the record
+func (a *auditRecorder) Audit(record AuditRecord) {
+	a.records = append(a.records, record)
+}
+
+// TestConfig_Audit will test that every send attempt is audited
+func TestConfig_Audit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<SendEmailResponse><SendEmailResult><MessageId>msg-1</MessageId></SendEmailResult></SendEmailResponse>`))
+	}))
+	defer server.Close()
+
+	recorder := &auditRecorder{}
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient, AuditSink: recorder}
+	result, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{"To@Example.com"}, Bcc: []string{"bcc@example.com"}, Subject: "subject"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MessageID != "msg-1" {
+		t.Errorf("Wrong message id: %s", result.MessageID)
+	}
+
+	cfg.HTTPClient = &mockHTTPBadRequest{}
+	attachment := base64.StdEncoding.EncodeToString([]byte(textBody))
+	if _, err = cfg.SendRaw(context.Background(), &RawMessage{
+		Data: []byte(fmt.Sprintf(rawBody, "to@example.com", "Sender <from@example.com>", textBody, len(attachment), attachment)),
+	}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(recorder.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recorder.records))
+	}
+	sent, failed := recorder.records[0], recorder.records[1]
+	if sent.Region != "region" {
+		t.Errorf("Wrong region: %s", sent.Region)
+	}
+	if sent.Action != "SendEmail" || sent.Status != AuditStatusSent || sent.MessageID != "msg-1" || sent.From != "from@example.com" {
+		t.Errorf("Wrong sent record: %+v", sent)
+	}
+	if len(sent.RecipientHashes) != 2 || sent.RecipientHashes[0] != (SHA256Redactor{}).Redact("to@example.com") {
+		t.Errorf("Wrong recipient hashes: %v", sent.RecipientHashes)
+	}
+	if failed.Action != "SendRawEmail" || failed.Status != AuditStatusFailed || failed.Error != "error code 400" {
+		t.Errorf("Wrong failed record: %+v", failed)
+	}
+	if failed.From != "from@example.com" || len(failed.RecipientHashes) != 1 {
+		t.Errorf("Wrong raw addresses: %+v", failed)
+	}
+}
+
+// TestConfig_AuditRejected will test guard rejections are audited without their details
+func TestConfig_AuditRejected(t *testing.T) {
+	recorder := &auditRecorder{}
+	cfg := Config{Region: "region", HTTPClient: &mockHTTPBadRequest{}, AuditSink: recorder, FromPolicy: &FromPolicy{Domains: []string{"example.com"}}}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@other.com", To: []string{"to@example.com"}, Subject: "subject"}); !errors.Is(err, ErrFromNotAllowed) {
+		t.Fatalf("expected ErrFromNotAllowed, got %v", err)
+	}
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: from@other.com\r\nTo: to@example.com\r\n\r\nbody")}); !errors.Is(err, ErrFromNotAllowed) {
+		t.Fatalf("expected ErrFromNotAllowed, got %v", err)
+	}
+
+	if len(recorder.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recorder.records))
+	}
+	for _, record := range recorder.records {
+		if record.Status != AuditStatusFailed || record.Error != ErrFromNotAllowed.Error() || len(record.RecipientHashes) != 1 {
+			t.Errorf("Wrong rejected record: %+v", record)
+		}
+	}
+}
+
+// TestConfig_AuditInvalid will test the messages failing validation are audited
+func TestConfig_AuditInvalid(t *testing.T) {
+	recorder := &auditRecorder{}
+	cfg := Config{Region: "region", HTTPClient: &mockHTTPBadRequest{}, AuditSink: recorder}
+	if _, err := cfg.Send(context.Background(), &Message{To: []string{"to@example.com"}, Subject: "subject"}); !errors.Is(err, ErrMissingFrom) {
+		t.Fatalf("expected ErrMissingFrom, got %v", err)
+	}
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{}); !errors.Is(err, ErrMissingRawData) {
+		t.Fatalf("expected ErrMissingRawData, got %v", err)
+	}
+
+	if len(recorder.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recorder.records))
+	}
+	for i, expected := range []error{ErrMissingFrom, ErrMissingRawData} {
+		if record := recorder.records[i]; record.Status != AuditStatusFailed || record.Error != expected.Error() {
+			t.Errorf("Wrong invalid record: %+v", record)
+		}
+	}
+	if len(recorder.records[0].RecipientHashes) != 1 || len(recorder.records[1].RecipientHashes) != 0 {
+		t.Errorf("Wrong recipients: %+v", recorder.records)
+	}
+}
+
+// TestAuditError will test error details are not recorded
+func TestAuditError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{&ResponseError{StatusCode: 400, Body: []byte("Invalid address: to@example.com")}, "error code 400"},
+		{fmt.Errorf("%w: to@example.com", ErrUnverifiedRecipient), ErrUnverifiedRecipient.Error()},
+		{fmt.Errorf("send: %w", context.DeadlineExceeded), context.DeadlineExceeded.Error()},
+		{errors.New("to@example.com"), "*errors.errorString"},
+	}
+	for _, test := range tests {
+		if output := auditError(test.err); output != test.expected {
+			t.Errorf("%s Expected [%s] and got [%s]", t.Name(), test.expected, output)
+		}
+	}
+}
+
+// TestJSONLinesAuditSink will test the JSON Lines sink
+func TestJSONLinesAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLinesAuditSink(&buf)
+	sink.Audit(AuditRecord{Action: "SendEmail", Status: AuditStatusSent, MessageID: "1"})
+	sink.Audit(AuditRecord{Action: "SendEmail", Status: AuditStatusFailed, Error: "boom"})
+	if err := sink.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Error != "boom" {
+		t.Errorf("Wrong record: %+v", record)
+	}
+}
+
+// TestOpenJSONLinesAuditFile will test the file sink appends
+func TestOpenJSONLinesAuditFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		var sink *JSONLinesAuditSink
+		if sink, err = OpenJSONLinesAuditFile(path); err != nil {
+			t.Fatal(err)
+		}
+		sink.Audit(AuditRecord{Action: "SendEmail"})
+		if err = sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("expected 2 lines, got %d", lines)
+	}
+}
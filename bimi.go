@@ -0,0 +1,73 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// BIMI headers: the sender sets BIMI-Selector, the receivers add (and remove any
+// sender supplied) BIMI-Location and BIMI-Indicator
+const (
+	HeaderBIMIIndicator = "BIMI-Indicator"
+	HeaderBIMILocation  = "BIMI-Location"
+	HeaderBIMISelector  = "BIMI-Selector"
+)
+
+// BIMI errors
+var (
+	ErrBIMIDomainMismatch  = errors.New("from domain does not match the bimi domain")
+	ErrInvalidBIMISelector = errors.New("invalid bimi selector")
+)
+
+// bimiSelectorPattern is a DNS label (the selector is published at <selector>._bimi.<domain>)
+var bimiSelectorPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// BIMISelector is the BIMI record a sender has published for a domain
+type BIMISelector struct {
+	// Domain is the domain of the BIMI record (the From domain or its parent domain)
+	Domain string
+
+	// Selector is the selector of the record (<selector>._bimi.<domain>, "default" if empty)
+	Selector string
+}
+
+// Validate will check the selector and that the From address is in the BIMI domain
+func (b *BIMISelector) Validate(from string) error {
+	if len(b.Selector) > 0 && !bimiSelectorPattern.MatchString(b.Selector) {
+		return fmt.Errorf("%w: %q", ErrInvalidBIMISelector, b.Selector)
+	}
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return err
+	}
+	domain := strings.ToLower(strings.TrimSuffix(b.Domain, "."))
+	for _, candidate := range domainCandidates(address.Address) {
+		if candidate == domain {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in %s", ErrBIMIDomainMismatch, address.Address, b.Domain)
+}
+
+// SetBIMISelectorHeader will validate the selector against the From header, set the
+// BIMI-Selector header and remove any BIMI-Location and BIMI-Indicator headers
+func (m *RawMessage) SetBIMISelectorHeader(b *BIMISelector) error {
+	parts := splitRaw(m.Data)
+	if err := b.Validate(parts.get("From")); err != nil {
+		return err
+	}
+	selector := b.Selector
+	if len(selector) == 0 {
+		selector = "default"
+	}
+	if err := parts.set(HeaderBIMISelector, "v=BIMI1; s="+selector); err != nil {
+		return err
+	}
+	parts.del(HeaderBIMILocation)
+	parts.del(HeaderBIMIIndicator)
+	m.Data = parts.bytes()
+	return nil
+}
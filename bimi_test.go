@@ -0,0 +1,59 @@
+package ses
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRawMessage_SetBIMISelectorHeader will test the method SetBIMISelectorHeader()
+func TestRawMessage_SetBIMISelectorHeader(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: Brand <news@mail.example.com>\r\nBIMI-Location: l=https://evil.com/logo.svg\r\n" +
+		"BIMI-Indicator: abc\r\n\r\nbody")}
+	if err := msg.SetBIMISelectorHeader(&BIMISelector{Domain: "example.com", Selector: "brand2024"}); err != nil {
+		t.Fatal(err)
+	}
+	parts := splitRaw(msg.Data)
+	if parts.get(HeaderBIMISelector) != "v=BIMI1; s=brand2024" {
+		t.Errorf("Wrong selector: %s", parts.get(HeaderBIMISelector))
+	}
+	if strings.Contains(string(msg.Data), "BIMI-Location") || strings.Contains(string(msg.Data), "BIMI-Indicator") {
+		t.Errorf("Expected the receiver headers to be removed: %s", msg.Data)
+	}
+
+	// Default selector
+	msg = &RawMessage{Data: []byte("From: news@example.com\r\n\r\nbody")}
+	if err := msg.SetBIMISelectorHeader(&BIMISelector{Domain: "Example.com."}); err != nil {
+		t.Fatal(err)
+	}
+	if splitRaw(msg.Data).get(HeaderBIMISelector) != "v=BIMI1; s=default" {
+		t.Errorf("Wrong selector: %s", msg.Data)
+	}
+}
+
+// TestBIMISelector_Validate will test the validation errors
+func TestBIMISelector_Validate(t *testing.T) {
+	tests := []struct {
+		selector BIMISelector
+		from     string
+		expected error
+	}{
+		{BIMISelector{Domain: "example.com"}, "news@other.com", ErrBIMIDomainMismatch},
+		{BIMISelector{Domain: "mail.example.com"}, "news@example.com", ErrBIMIDomainMismatch},
+		{BIMISelector{Domain: "example.com", Selector: "bad selector"}, "news@example.com", ErrInvalidBIMISelector},
+		{BIMISelector{Domain: "example.com", Selector: "-bad"}, "news@example.com", ErrInvalidBIMISelector},
+	}
+	for _, test := range tests {
+		if err := test.selector.Validate(test.from); !errors.Is(err, test.expected) {
+			t.Errorf("%+v %s: expected %v got %v", test.selector, test.from, test.expected, err)
+		}
+	}
+
+	msg := &RawMessage{Data: []byte("From: news@other.com\r\n\r\nbody")}
+	if err := msg.SetBIMISelectorHeader(&BIMISelector{Domain: "example.com"}); !errors.Is(err, ErrBIMIDomainMismatch) {
+		t.Errorf("expected ErrBIMIDomainMismatch got %v", err)
+	}
+	if strings.Contains(string(msg.Data), HeaderBIMISelector) {
+		t.Errorf("Expected the message to be unchanged")
+	}
+}
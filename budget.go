@@ -0,0 +1,121 @@
+package ses
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// GmailClipSize is the HTML size above which Gmail clips messages ("[Message clipped]")
+const GmailClipSize = 102 * 1024
+
+// HTMLSizeBudget measures the HTML body of formatted messages and warns, or truncates it
+// with a "view in browser" link, when it is over the budget
+type HTMLSizeBudget struct {
+	// MaxSize is the max size of the HTML body in bytes (default GmailClipSize)
+	MaxSize int
+
+	// Truncate cuts the HTML body to fit the budget (otherwise only OnOverBudget is called)
+	Truncate bool
+
+	// ViewInBrowserURL is the link added where the HTML body is truncated (optional)
+	ViewInBrowserURL string
+
+	// ViewInBrowserText is the text of the link (default "View the full message in your browser")
+	ViewInBrowserText string
+
+	// OnOverBudget is called with the size and max size of HTML bodies over the budget (optional)
+	OnOverBudget func(size, maxSize int)
+}
+
+var (
+	htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)[^>]*?(/?)>`)
+	voidElements   = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+	}
+)
+
+// Apply returns the HTML to send, and true if it was truncated
+func (b *HTMLSizeBudget) Apply(body string) (string, bool) {
+	maxSize := b.MaxSize
+	if maxSize <= 0 {
+		maxSize = GmailClipSize
+	}
+	if len(body) <= maxSize {
+		return body, false
+	}
+	if b.OnOverBudget != nil {
+		b.OnOverBudget(len(body), maxSize)
+	}
+	if !b.Truncate {
+		return body, false
+	}
+
+	fallback := b.fallback()
+	for budget := maxSize - len(fallback); budget > 0; {
+		prefix := safeCut(body, budget)
+		truncated := prefix + fallback + closingTags(prefix)
+		if len(truncated) <= maxSize {
+			return truncated, true
+		}
+		budget -= len(truncated) - maxSize
+	}
+	return fallback, true
+}
+
+// fallback returns the "view in browser" html
+func (b *HTMLSizeBudget) fallback() string {
+	if len(b.ViewInBrowserURL) == 0 {
+		return ""
+	}
+	text := b.ViewInBrowserText
+	if len(text) == 0 {
+		text = "View the full message in your browser"
+	}
+	return `<p><a href="` + html.EscapeString(b.ViewInBrowserURL) + `">` + html.EscapeString(text) + `</a></p>`
+}
+
+// safeCut returns the html cut at (or before) size without splitting a tag, an entity or a character
+func safeCut(body string, size int) string {
+	if size >= len(body) {
+		return body
+	}
+	for size > 0 && !utf8.RuneStart(body[size]) {
+		size--
+	}
+	prefix := body[:size]
+	if i := strings.LastIndex(prefix, "<"); i > strings.LastIndex(prefix, ">") {
+		prefix = prefix[:i]
+	}
+	if i := strings.LastIndex(prefix, "&"); i > strings.LastIndex(prefix, ";") {
+		prefix = prefix[:i]
+	}
+	return prefix
+}
+
+// closingTags returns the closing tags of the elements left open in the html
+func closingTags(body string) string {
+	var open []string
+	for _, m := range htmlTagPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.ToLower(m[2])
+		switch {
+		case voidElements[name] || m[3] == "/":
+		case m[1] == "/":
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					open = open[:i]
+					break
+				}
+			}
+		default:
+			open = append(open, name)
+		}
+	}
+	var closing strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		closing.WriteString("</" + open[i] + ">")
+	}
+	return closing.String()
+}
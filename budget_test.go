@@ -0,0 +1,84 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHTMLSizeBudget_Apply will test the method Apply()
+func TestHTMLSizeBudget_Apply(t *testing.T) {
+	body := "<html><body><table><tr><td>" + strings.Repeat("caf&eacute; é <b>bold</b><br> ", 20) + "</td></tr></table></body></html>"
+
+	// Under the budget
+	if out, truncated := (&HTMLSizeBudget{Truncate: true}).Apply(body); out != body || truncated {
+		t.Errorf("Expected no change")
+	}
+
+	// Warning only
+	var warned []int
+	budget := &HTMLSizeBudget{MaxSize: 200, OnOverBudget: func(size, maxSize int) { warned = append(warned, size, maxSize) }}
+	if out, truncated := budget.Apply(body); out != body || truncated {
+		t.Errorf("Expected no change without Truncate")
+	}
+	if len(warned) != 2 || warned[0] != len(body) || warned[1] != 200 {
+		t.Errorf("Wrong warning: %v", warned)
+	}
+
+	// Truncated with the fallback link
+	budget.Truncate, budget.ViewInBrowserURL = true, "https://example.com/view?id=1&t=2"
+	out, truncated := budget.Apply(body)
+	if !truncated || len(out) > 200 {
+		t.Fatalf("Expected a truncated body of at most 200 bytes got %d: %s", len(out), out)
+	}
+	if !strings.Contains(out, `<p><a href="https://example.com/view?id=1&amp;t=2">View the full message in your browser</a></p>`) {
+		t.Errorf("Missing the fallback link: %s", out)
+	}
+	if !strings.HasSuffix(out, "</td></tr></table></body></html>") {
+		t.Errorf("Expected the open tags to be closed: %s", out)
+	}
+}
+
+// TestSafeCut will test cutting the html without splitting tags, entities or characters
+func TestSafeCut(t *testing.T) {
+	tests := []struct {
+		body     string
+		size     int
+		expected string
+	}{
+		{"<p>hello</p>", 10, "<p>hello"},
+		{"<p>a&amp;b</p>", 7, "<p>a"},
+		{"<p>é</p>", 4, "<p>"},
+		{"<p>hi</p>", 100, "<p>hi</p>"},
+	}
+	for _, test := range tests {
+		if out := safeCut(test.body, test.size); out != test.expected {
+			t.Errorf("safeCut(%q, %d): expected %q got %q", test.body, test.size, test.expected, out)
+		}
+	}
+	if closing := closingTags("<div><p>a<br><img src='x'/><span>b</span>"); closing != "</p></div>" {
+		t.Errorf("Wrong closing tags: %s", closing)
+	}
+}
+
+// TestConfig_SendHTMLSizeBudget will test the size reported on the send result
+func TestConfig_SendHTMLSizeBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		HTMLSizeBudget: &HTMLSizeBudget{MaxSize: 100, Truncate: true},
+	}
+	result, err := cfg.Send(context.Background(), &Message{
+		From: "from@example.com", To: []string{to}, Subject: "s", HTMLBody: "<p>" + strings.Repeat("a", 200) + "</p>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Truncated || result.HTMLSize != 100 {
+		t.Errorf("Wrong result: %+v", result)
+	}
+}
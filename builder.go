@@ -0,0 +1,135 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// BuildSendEmailForm returns the SendEmail (v1 API) parameters that Send would send for the
+// message, so application tests can assert on them without an HTTP layer. The message goes
+// through the same defaults, guards and transformers as Send (the guards can call SES) and
+// the options are applied. The AWSAccessKeyId parameter is not included
+func (c *Config) BuildSendEmailForm(ctx context.Context, m *Message, opts ...SendOption) (url.Values, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	o := newSendOptions(opts)
+	msg := *m
+	if _, err := c.prepare(ctx, &msg, o); err != nil {
+		return nil, err
+	}
+	req, err := (&QueryMarshaler{}).MarshalMessage(&msg)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyRawParams(req, o.rawParams); err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	values.Del("AWSAccessKeyId")
+	return values, nil
+}
+
+// BuildRawMIME composes the message as a MIME message (for SendRawEmail or to assert on the
+// headers and encoded parts), after the same defaults, guards and transformers as Send (the
+// guards can call SES). Bcc addresses are not written to the headers. The output is
+// deterministic: the same message always builds the same bytes
+func (c *Config) BuildRawMIME(ctx context.Context, m *Message) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	msg := *m
+	if _, err := c.prepare(ctx, &msg, newSendOptions(nil)); err != nil {
+		return nil, err
+	}
+	m = &msg
+
+	var buf bytes.Buffer
+	for _, header := range []struct {
+		key       string
+		addresses []string
+	}{
+		{"From", []string{m.From}}, {"To", m.To}, {"Cc", m.Cc}, {"Reply-To", m.ReplyTo},
+	} {
+		value, err := encodeAddresses(header.key, header.addresses)
+		if err != nil {
+			return nil, err
+		}
+		writeHeader(&buf, header.key, value)
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	switch {
+	case len(m.HTMLBody) > 0 && len(m.TextBody) > 0:
+		sum := sha256.Sum256([]byte(m.TextBody + "\x00" + m.HTMLBody))
+		boundary := hex.EncodeToString(sum[:16])
+		writeHeader(&buf, "Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+		buf.WriteString("\r\n")
+		for _, part := range []struct{ contentType, body string }{
+			{"text/plain", m.TextBody}, {"text/html", m.HTMLBody},
+		} {
+			buf.WriteString("--" + boundary + "\r\n")
+			if err := writeTextPart(&buf, part.contentType, part.body); err != nil {
+				return nil, err
+			}
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString("--" + boundary + "--\r\n")
+	case len(m.HTMLBody) > 0:
+		if err := writeTextPart(&buf, "text/html", m.HTMLBody); err != nil {
+			return nil, err
+		}
+	default:
+		if err := writeTextPart(&buf, "text/plain", m.TextBody); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeAddresses parses the addresses and joins them encoded for a header (non-ASCII
+// display names are encoded, addresses with line breaks are rejected)
+func encodeAddresses(key string, addresses []string) (string, error) {
+	encoded := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if err := validateHeader(key, address); err != nil {
+			return "", err
+		}
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return "", err
+		}
+		encoded = append(encoded, parsed.String())
+	}
+	return strings.Join(encoded, ", "), nil
+}
+
+// writeHeader writes the header line if the value is set
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	if len(value) > 0 {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+}
+
+// writeTextPart writes the headers and quoted-printable body of a text part
+func writeTextPart(buf *bytes.Buffer, contentType, body string) error {
+	writeHeader(buf, "Content-Type", contentType+`; charset="utf-8"`)
+	writeHeader(buf, "Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+	writer := quotedprintable.NewWriter(buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,130 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+)
+
+// TestBuildSendEmailForm will test the method BuildSendEmailForm()
+func TestBuildSendEmailForm(t *testing.T) {
+	cfg := &Config{ConfigurationSet: "default"}
+	values, err := cfg.BuildSendEmailForm(context.Background(), &Message{
+		From: "from@example.com", To: []string{"a@example.com", "b@example.com"}, Subject: "Hi", TextBody: "text",
+		Tags: map[string]string{"campaign": "welcome"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Action":                           "SendEmail",
+		"Source":                           "from@example.com",
+		"Destination.ToAddresses.member.2": "b@example.com",
+		"Message.Subject.Data":             "Hi",
+		"Message.Body.Text.Data":           "text",
+		"Tags.member.1.Name":               "campaign",
+		"Tags.member.1.Value":              "welcome",
+		"ConfigurationSetName":             "default",
+	}
+	for key, value := range expected {
+		if values.Get(key) != value {
+			t.Errorf("%s: expected %q got %q", key, value, values.Get(key))
+		}
+	}
+	if _, ok := values["AWSAccessKeyId"]; ok {
+		t.Errorf("Expected no AWSAccessKeyId")
+	}
+
+	if _, err = cfg.BuildSendEmailForm(context.Background(), &Message{To: []string{to}}); !errors.Is(err, ErrMissingFrom) {
+		t.Errorf("expected ErrMissingFrom got %v", err)
+	}
+
+	// Runs the same guards as Send
+	cfg.FromPolicy = &FromPolicy{Domains: []string{"example.com"}}
+	if _, err = cfg.BuildSendEmailForm(context.Background(), &Message{From: "from@other.com", To: []string{to}}); !errors.Is(err, ErrFromNotAllowed) {
+		t.Errorf("expected ErrFromNotAllowed got %v", err)
+	}
+}
+
+// TestBuildRawMIME will test the method BuildRawMIME()
+func TestBuildRawMIME(t *testing.T) {
+	m := &Message{
+		From: "Brand <from@example.com>", To: []string{"a@example.com"}, Cc: []string{"c@example.com"},
+		Bcc: []string{"secret@example.com"}, Subject: "Café", TextBody: "héllo", HTMLBody: "<p>héllo</p>",
+	}
+	cfg := &Config{}
+	raw, err := cfg.BuildRawMIME(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := cfg.BuildRawMIME(context.Background(), m)
+	if !bytes.Equal(raw, again) {
+		t.Errorf("Expected a deterministic output")
+	}
+	if bytes.Contains(raw, []byte("secret@example.com")) {
+		t.Errorf("Expected no Bcc header")
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Café" || msg.Header.Get("Cc") != "<c@example.com>" {
+		t.Errorf("Wrong headers: %v", msg.Header)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Wrong content type: %s", mediaType)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, expected := range []string{"héllo", "<p>héllo</p>"} {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(part)
+		if string(body) != expected {
+			t.Errorf("expected %q got %q", expected, body)
+		}
+	}
+
+	// A single part
+	raw, _ = cfg.BuildRawMIME(context.Background(), &Message{From: "from@example.com", To: []string{to}, Subject: "s", TextBody: "text"})
+	if msg, _ = mail.ReadMessage(bytes.NewReader(raw)); msg.Header.Get("Content-Type") != `text/plain; charset="utf-8"` {
+		t.Errorf("Wrong content type: %s", msg.Header.Get("Content-Type"))
+	}
+}
+
+// TestBuildRawMIME_Addresses will test the address headers are encoded and checked
+func TestBuildRawMIME_Addresses(t *testing.T) {
+	cfg := &Config{}
+	raw, err := cfg.BuildRawMIME(context.Background(), &Message{
+		From: "Zoë <from@example.com>", To: []string{"José <a@example.com>", "b@example.com"}, Subject: "s", TextBody: "text",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from := msg.Header.Get("From"); from != "=?utf-8?q?Zo=C3=AB?= <from@example.com>" {
+		t.Errorf("Wrong From: %s", from)
+	}
+	list, err := msg.Header.AddressList("To")
+	if err != nil || len(list) != 2 || list[0].Name != "José" || list[1].Address != "b@example.com" {
+		t.Errorf("Wrong To: %v %v", list, err)
+	}
+
+	if _, err = cfg.BuildRawMIME(context.Background(), &Message{
+		From: "from@example.com", To: []string{"a@example.com\r\nBcc: victim@example.com"}, Subject: "s", TextBody: "text",
+	}); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected ErrInvalidHeader got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package ses
+
+import "net/http"
+
+// Operation is a Config method and the SES action it calls for each API version
+type Operation struct {
+	// Method is the name of the Config method (Send, SendRaw, GetTemplate...)
+	Method string `json:"method"`
+
+	// Versions are the SES actions by API version (APIVersionV1 or APIVersionV2)
+	Versions map[string]*Action `json:"versions"`
+}
+
+// Action is an SES API action
+type Action struct {
+	// Name is the name of the SES action
+	Name string `json:"name"`
+
+	// IAMAction is the IAM action needed to call it
+	IAMAction string `json:"iamAction"`
+
+	// HTTPMethod and Path are the HTTP request (Path is empty for the v1 query API)
+	HTTPMethod string `json:"httpMethod"`
+	Path       string `json:"path,omitempty"`
+
+	// Parameters are the parameters sent by the client ("member.N" lists for the v1 query API)
+	Parameters []ActionParameter `json:"parameters"`
+}
+
+// ActionParameter is a parameter of an SES action
+type ActionParameter struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+}
+
+// Supports returns true if the operation is available for the API version
+func (o *Operation) Supports(version string) bool {
+	_, ok := o.Versions[version]
+	return ok
+}
+
+// required and optional build the action parameters
+func required(names ...string) []ActionParameter { return parameters(true, names) }
+func optional(names ...string) []ActionParameter { return parameters(false, names) }
+
+// parameters builds the action parameters
+func parameters(isRequired bool, names []string) []ActionParameter {
+	list := make([]ActionParameter, 0, len(names))
+	for _, name := range names {
+		list = append(list, ActionParameter{Name: name, Required: isRequired})
+	}
+	return list
+}
+
+// queryAction returns a v1 query API action
+func queryAction(name string, params ...[]ActionParameter) *Action {
+	action := &Action{Name: name, IAMAction: "ses:" + name, HTTPMethod: http.MethodPost}
+	for _, list := range params {
+		action.Parameters = append(action.Parameters, list...)
+	}
+	return action
+}
+
+// Catalog returns the operations supported by the client, in a machine readable format
+// for tooling and code generation (a new copy on every call)
+func Catalog() []*Operation {
+	v1Tags := optional("Tags.member.N.Name", "Tags.member.N.Value")
+	v2SendEmail := func(params ...[]ActionParameter) *Action {
+		action := &Action{
+			Name: "SendEmail", IAMAction: "ses:SendEmail", HTTPMethod: http.MethodPost, Path: "/v2/email/outbound-emails",
+		}
+		for _, list := range params {
+			action.Parameters = append(action.Parameters, list...)
+		}
+		return action
+	}
+	templateParams := [][]ActionParameter{
+		required("Template.TemplateName", "Template.SubjectPart"), optional("Template.TextPart", "Template.HtmlPart"),
+	}
+
+	return []*Operation{
+		{Method: "Send", Versions: map[string]*Action{
+			APIVersionV1: queryAction("SendEmail",
+				required("Source", "Message.Subject.Data", "Message.Body.Text.Data"),
+				optional("Destination.ToAddresses.member.N", "Destination.CcAddresses.member.N",
+					"Destination.BccAddresses.member.N", "ReplyToAddresses.member.N", "Message.Body.Html.Data",
+					"ConfigurationSetName", "SourceArn"),
+				v1Tags,
+			),
+			APIVersionV2: v2SendEmail(
+				required("FromEmailAddress", "Content.Simple.Subject.Data"),
+				optional("Destination.ToAddresses", "Destination.CcAddresses", "Destination.BccAddresses",
+					"ReplyToAddresses", "Content.Simple.Body.Text.Data", "Content.Simple.Body.Html.Data",
+					"FromEmailAddressIdentityArn", "ConfigurationSetName", "EmailTags"),
+			),
+		}},
+		{Method: "SendRaw", Versions: map[string]*Action{
+			APIVersionV1: queryAction("SendRawEmail",
+				required("RawMessage.Data"), optional("ConfigurationSetName", "SourceArn", "FromArn"), v1Tags,
+			),
+			APIVersionV2: v2SendEmail(
+				required("Content.Raw.Data"), optional("FromEmailAddressIdentityArn", "ConfigurationSetName", "EmailTags"),
+			),
+		}},
+		{Method: "ListIdentities", Versions: map[string]*Action{
+			APIVersionV1: queryAction("ListIdentities", optional("NextToken")),
+		}},
+		{Method: "GetIdentityVerificationAttributes", Versions: map[string]*Action{
+			APIVersionV1: queryAction("GetIdentityVerificationAttributes", required("Identities.member.N")),
+		}},
+		{Method: "GetSendQuota", Versions: map[string]*Action{
+			APIVersionV1: queryAction("GetSendQuota"),
+		}},
+		{Method: "CreateTemplate", Versions: map[string]*Action{
+			APIVersionV1: queryAction("CreateTemplate", templateParams...),
+		}},
+		{Method: "UpdateTemplate", Versions: map[string]*Action{
+			APIVersionV1: queryAction("UpdateTemplate", templateParams...),
+		}},
+		{Method: "DeleteTemplate", Versions: map[string]*Action{
+			APIVersionV1: queryAction("DeleteTemplate", required("TemplateName")),
+		}},
+		{Method: "GetTemplate", Versions: map[string]*Action{
+			APIVersionV1: queryAction("GetTemplate", required("TemplateName")),
+		}},
+		{Method: "ListTemplates", Versions: map[string]*Action{
+			APIVersionV1: queryAction("ListTemplates", optional("NextToken")),
+		}},
+		{Method: "GetAccount", Versions: map[string]*Action{
+			APIVersionV2: {
+				Name: "GetAccount", IAMAction: "ses:GetAccount", HTTPMethod: http.MethodGet, Path: "/v2/email/account",
+			},
+		}},
+		{Method: "GetDomainDeliverabilityCampaign", Versions: map[string]*Action{
+			APIVersionV2: {
+				Name: "GetDomainDeliverabilityCampaign", IAMAction: "ses:GetDomainDeliverabilityCampaign",
+				HTTPMethod: http.MethodGet, Path: deliverabilityPath + "/campaigns/{CampaignId}",
+				Parameters: required("CampaignId"),
+			},
+		}},
+		{Method: "ListDomainDeliverabilityCampaigns", Versions: map[string]*Action{
+			APIVersionV2: {
+				Name: "ListDomainDeliverabilityCampaigns", IAMAction: "ses:ListDomainDeliverabilityCampaigns",
+				HTTPMethod: http.MethodGet, Path: deliverabilityPath + "/domains/{SubscribedDomain}/campaigns",
+				Parameters: append(required("SubscribedDomain", "StartDate", "EndDate"), optional("NextToken", "PageSize")...),
+			},
+		}},
+	}
+}
+
+// LookupOperation returns the operation of a Config method (nil if not found)
+func LookupOperation(method string) *Operation {
+	for _, operation := range Catalog() {
+		if operation.Method == method {
+			return operation
+		}
+	}
+	return nil
+}
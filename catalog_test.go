@@ -0,0 +1,87 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// memberIndex matches the index of a "member.N" parameter
+var memberIndex = regexp.MustCompile(`\.member\.\d+`)
+
+// assertParameters checks that every parameter sent is in the catalog
+func assertParameters(t *testing.T, action *Action, values url.Values) {
+	known := make(map[string]bool)
+	for _, param := range action.Parameters {
+		known[param.Name] = true
+	}
+	for key := range values {
+		if key == "Action" || key == "AWSAccessKeyId" {
+			continue
+		}
+		if name := memberIndex.ReplaceAllString(key, ".member.N"); !known[name] {
+			t.Errorf("%s: parameter %s is not in the catalog", action.Name, name)
+		}
+	}
+}
+
+// TestCatalog will test the catalog matches the client
+func TestCatalog(t *testing.T) {
+	configType := reflect.TypeOf(&Config{})
+	for _, operation := range Catalog() {
+		if _, ok := configType.MethodByName(operation.Method); !ok {
+			t.Errorf("Config has no method %s", operation.Method)
+		}
+		if len(operation.Versions) == 0 {
+			t.Errorf("%s: no versions", operation.Method)
+		}
+	}
+
+	// The parameters match what the marshalers send
+	m := &Message{
+		From: "from@example.com", To: []string{to}, Cc: []string{to}, Bcc: []string{to}, ReplyTo: []string{to},
+		Subject: "s", TextBody: "t", HTMLBody: "h", ConfigurationSet: "c", SourceArn: "arn", Tags: map[string]string{"a": "b"},
+	}
+	values, err := (&Config{}).BuildSendEmailForm(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertParameters(t, LookupOperation("Send").Versions[APIVersionV1], values)
+
+	req, err := (&QueryMarshaler{}).MarshalRawMessage(&RawMessage{
+		Data: []byte("raw"), ConfigurationSet: "c", SourceArn: "arn", Tags: map[string]string{"a": "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _ = url.ParseQuery(string(req.Body))
+	assertParameters(t, LookupOperation("SendRaw").Versions[APIVersionV1], values)
+
+	// The methods used for the IAM actions are in the catalog
+	cfg := &Config{VerifiedIdentities: NewVerifiedIdentityCache(0), Sandbox: NewSandboxGuard(NewVerifiedIdentityCache(0))}
+	sendMethods, guardMethods := cfg.iamMethods()
+	for _, method := range append(sendMethods, guardMethods...) {
+		if LookupOperation(method) == nil {
+			t.Errorf("IAM method %s is not in the catalog", method)
+		}
+	}
+}
+
+// TestLookupOperation will test the method LookupOperation()
+func TestLookupOperation(t *testing.T) {
+	operation := LookupOperation("GetDomainDeliverabilityCampaign")
+	if operation == nil || operation.Supports(APIVersionV1) || !operation.Supports(APIVersionV2) {
+		t.Errorf("Wrong operation: %+v", operation)
+	}
+	if LookupOperation("Unknown") != nil {
+		t.Errorf("Expected no operation")
+	}
+
+	// Every call returns a copy
+	LookupOperation("Send").Versions[APIVersionV1].Name = "changed"
+	if LookupOperation("Send").Versions[APIVersionV1].Name != "SendEmail" {
+		t.Errorf("Expected the catalog not to be shared")
+	}
+}
@@ -0,0 +1,60 @@
+package ses
+
+// ConfigOption overrides a field of a cloned Config (see Clone)
+type ConfigOption func(c *Config)
+
+// Clone returns a copy of the Config with the options applied, without modifying the
+// original Config. The credentials are copied, the HTTP client, marshaler, sinks, policies
+// and caches are shared (they are safe for concurrent use)
+func (c *Config) Clone(opts ...ConfigOption) *Config {
+	cfg := c.copy()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// OverrideRegion sets the region and its standard regional endpoint (clearing the SigningHost)
+func OverrideRegion(region string) ConfigOption {
+	return func(c *Config) {
+		c.Region = region
+		c.Endpoint = RegionEndpoint(region)
+		c.SigningHost = ""
+	}
+}
+
+// OverrideEndpoint sets the endpoint (clearing the SigningHost)
+func OverrideEndpoint(endpoint string) ConfigOption {
+	return func(c *Config) {
+		c.Endpoint = endpoint
+		c.SigningHost = ""
+	}
+}
+
+// OverrideConfigurationSet sets the default configuration set
+func OverrideConfigurationSet(name string) ConfigOption {
+	return func(c *Config) {
+		c.ConfigurationSet = name
+	}
+}
+
+// OverrideSourceArn sets the default sending authorization ARN
+func OverrideSourceArn(arn string) ConfigOption {
+	return func(c *Config) {
+		c.SourceArn = arn
+	}
+}
+
+// OverrideHTTPClient sets the http client
+func OverrideHTTPClient(client httpInterface) ConfigOption {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// OverrideCredentials sets the credentials
+func OverrideCredentials(accessKeyID, secretAccessKey, sessionToken string) ConfigOption {
+	return func(c *Config) {
+		c.AccessKeyID, c.SecretAccessKey, c.SessionToken = accessKeyID, secretAccessKey, sessionToken
+	}
+}
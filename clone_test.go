@@ -0,0 +1,46 @@
+package ses
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestConfig_Clone will test the method Clone()
+func TestConfig_Clone(t *testing.T) {
+	sink := &auditRecorder{}
+	cfg := &Config{
+		Endpoint: "https://email.us-east-1.amazonaws.com", Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s",
+		HTTPClient: http.DefaultClient, ConfigurationSet: "default", AuditSink: sink,
+	}
+	client := &http.Client{}
+	clone := cfg.Clone(
+		OverrideRegion("eu-west-1"),
+		OverrideConfigurationSet("tenant"),
+		OverrideSourceArn("arn"),
+		OverrideHTTPClient(client),
+		OverrideCredentials("a2", "s2", "t2"),
+	)
+	if clone.Region != "eu-west-1" || clone.Endpoint != "https://email.eu-west-1.amazonaws.com" ||
+		clone.ConfigurationSet != "tenant" || clone.SourceArn != "arn" || clone.HTTPClient != client {
+		t.Errorf("Wrong clone: %+v", clone)
+	}
+	if creds := clone.credentials(); creds.AccessKeyID != "a2" || creds.SecretAccessKey != "s2" || creds.SessionToken != "t2" {
+		t.Errorf("Wrong credentials: %+v", creds)
+	}
+	if clone.AuditSink != sink {
+		t.Errorf("Expected the audit sink to be shared")
+	}
+	if cfg.Region != "us-east-1" || cfg.ConfigurationSet != "default" || cfg.AccessKeyID != "a" || cfg.HTTPClient != http.DefaultClient {
+		t.Errorf("Expected the original to be unchanged: %+v", cfg)
+	}
+
+	// Rotating the clone credentials does not rotate the original
+	clone.SetCredentials("a3", "s3", "")
+	if cfg.credentials().AccessKeyID != "a" {
+		t.Errorf("Expected the original credentials to be unchanged")
+	}
+
+	if endpoint := cfg.Clone(OverrideEndpoint("https://proxy")).Endpoint; endpoint != "https://proxy" {
+		t.Errorf("Wrong endpoint: %s", endpoint)
+	}
+}
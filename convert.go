@@ -0,0 +1,134 @@
+package ses
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ErrUnsupportedContent is returned when a MIME message can't be converted into a Message
+// (attachments, inline images...), send those with ReadRawMessage() and SendRaw()
+var ErrUnsupportedContent = errors.New("content can't be converted into a formatted message")
+
+// ReadMessage converts an RFC 5322 message (from gomail, net/smtp...) into a formatted
+// Message, see MessageFromMail()
+func ReadMessage(r io.Reader) (*Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	return MessageFromMail(msg)
+}
+
+// MessageFromMail converts a parsed message into a formatted Message. The body must be
+// text/plain, text/html or a multipart/alternative of those (utf-8 or us-ascii)
+func MessageFromMail(msg *mail.Message) (*Message, error) {
+	m := &Message{}
+	from, err := headerAddresses(msg.Header, "From")
+	if err != nil {
+		return nil, err
+	} else if len(from) > 0 {
+		m.From = from[0]
+	}
+	fields := []struct {
+		key   string
+		value *[]string
+	}{{"To", &m.To}, {"Cc", &m.Cc}, {"Bcc", &m.Bcc}, {"Reply-To", &m.ReplyTo}}
+	for _, field := range fields {
+		if *field.value, err = headerAddresses(msg.Header, field.key); err != nil {
+			return nil, err
+		}
+	}
+	if m.Subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		return nil, err
+	}
+	if err = readBodies(m, textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, err
+	}
+	return m, m.Validate()
+}
+
+// ReadRawMessage reads an RFC 5322 message into a RawMessage, checking that it parses and
+// has a From and at least one recipient
+func ReadRawMessage(r io.Reader) (*RawMessage, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = mail.ParseAddress(msg.Header.Get("From")); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMissingFrom, err)
+	}
+	if len(msg.Header.Get("To")+msg.Header.Get("Cc")+msg.Header.Get("Bcc")) == 0 {
+		return nil, ErrMissingRecipients
+	}
+	return &RawMessage{Data: data}, nil
+}
+
+// headerAddresses returns the addresses of the header (nil if missing)
+func headerAddresses(header mail.Header, key string) ([]string, error) {
+	if len(header.Get(key)) == 0 {
+		return nil, nil
+	}
+	list, err := header.AddressList(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	addresses := make([]string, 0, len(list))
+	for _, address := range list {
+		addresses = append(addresses, address.String())
+	}
+	return addresses, nil
+}
+
+// readBodies sets the text and html bodies of the message from a MIME entity
+func readBodies(m *Message, header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition")); disposition == "attachment" {
+		return fmt.Errorf("%w: attachment", ErrUnsupportedContent)
+	}
+	if charset := strings.ToLower(params["charset"]); len(charset) > 0 && charset != "utf-8" && charset != "us-ascii" {
+		return fmt.Errorf("%w: charset %s", ErrUnsupportedContent, charset)
+	}
+
+	switch mediaType {
+	case "multipart/alternative":
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err = readBodies(m, part.Header, part); err != nil {
+				return err
+			}
+		}
+	case "text/plain", "text/html":
+		data, err := ioutil.ReadAll(transferDecoder(header, body))
+		if err != nil {
+			return err
+		}
+		if mediaType == "text/html" {
+			m.HTMLBody = string(data)
+		} else {
+			m.TextBody = string(data)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedContent, mediaType)
+}
@@ -0,0 +1,93 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestReadMessage will test converting a MIME message into a Message
+func TestReadMessage(t *testing.T) {
+	raw := "From: \"Brand\" <from@example.com>\r\n" +
+		"To: a@example.com, B <b@example.com>\r\n" +
+		"Cc: c@example.com\r\n" +
+		"Reply-To: reply@example.com\r\n" +
+		"Subject: =?utf-8?q?Caf=C3=A9?=\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/alternative; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"h=C3=A9llo\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/html; charset=\"UTF-8\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"PHA+aGk8L3A+\r\n" +
+		"--b--\r\n"
+
+	m, err := ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.From != `"Brand" <from@example.com>` || len(m.To) != 2 || m.To[1] != `"B" <b@example.com>` ||
+		m.Cc[0] != "<c@example.com>" || m.ReplyTo[0] != "<reply@example.com>" || len(m.Bcc) != 0 {
+		t.Errorf("Wrong addresses: %+v", m)
+	}
+	if m.Subject != "Café" || m.TextBody != "héllo" || m.HTMLBody != "<p>hi</p>" {
+		t.Errorf("Wrong content: %+v", m)
+	}
+
+	// A round trip through BuildRawMIME
+	built, err := (&Config{}).BuildRawMIME(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadMessage(strings.NewReader(string(built)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Subject != m.Subject || again.TextBody != m.TextBody || again.HTMLBody != m.HTMLBody {
+		t.Errorf("Wrong round trip: %+v", again)
+	}
+}
+
+// TestReadMessage_Errors will test the unsupported messages
+func TestReadMessage_Errors(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected error
+	}{
+		{"From: a@example.com\r\nTo: b@example.com\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n--b--\r\n", ErrUnsupportedContent},
+		{"From: a@example.com\r\nTo: b@example.com\r\nContent-Type: text/plain; charset=iso-8859-1\r\n\r\nbody", ErrUnsupportedContent},
+		{"From: a@example.com\r\nTo: b@example.com\r\nContent-Disposition: attachment\r\n\r\nbody", ErrUnsupportedContent},
+		{"To: b@example.com\r\n\r\nbody", ErrMissingFrom},
+		{"From: a@example.com\r\n\r\nbody", ErrMissingRecipients},
+	}
+	for _, test := range tests {
+		if _, err := ReadMessage(strings.NewReader(test.raw)); !errors.Is(err, test.expected) {
+			t.Errorf("%q: expected %v got %v", test.raw, test.expected, err)
+		}
+	}
+}
+
+// TestReadRawMessage will test reading a raw message
+func TestReadRawMessage(t *testing.T) {
+	raw := "From: a@example.com\r\nBcc: b@example.com\r\n\r\nbody"
+	m, err := ReadRawMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Data) != raw {
+		t.Errorf("Wrong data: %s", m.Data)
+	}
+	if _, err = ReadRawMessage(strings.NewReader("To: b@example.com\r\n\r\nbody")); !errors.Is(err, ErrMissingFrom) {
+		t.Errorf("expected ErrMissingFrom got %v", err)
+	}
+	if _, err = ReadRawMessage(strings.NewReader("From: a@example.com\r\n\r\nbody")); !errors.Is(err, ErrMissingRecipients) {
+		t.Errorf("expected ErrMissingRecipients got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package ses
+
+import "context"
+
+// Defaults are the values stamped on every message sent with a context (see WithDefaults),
+// the message values win over the context, and the context wins over the Config
+type Defaults struct {
+	// ConfigurationSet is the configuration set for messages that do not set one
+	ConfigurationSet string
+
+	// Tags are added to the message tags (tenant, campaign...)
+	Tags map[string]string
+
+	// Headers are added to raw messages that do not have them (formatted messages can't carry
+	// headers). A key or value that would inject other headers fails the send with ErrInvalidHeader
+	Headers map[string]string
+}
+
+// defaultsKey is the context key of the Defaults
+type defaultsKey struct{}
+
+// WithDefaults returns a context carrying the defaults, merged over the defaults already
+// in the context (so middleware layers can each add their own)
+func WithDefaults(ctx context.Context, defaults *Defaults) context.Context {
+	merged := &Defaults{Tags: map[string]string{}, Headers: map[string]string{}}
+	for _, d := range []*Defaults{DefaultsFromContext(ctx), defaults} {
+		if d == nil {
+			continue
+		}
+		if len(d.ConfigurationSet) > 0 {
+			merged.ConfigurationSet = d.ConfigurationSet
+		}
+		for name, value := range d.Tags {
+			merged.Tags[name] = value
+		}
+		for key, value := range d.Headers {
+			merged.Headers[key] = value
+		}
+	}
+	return context.WithValue(ctx, defaultsKey{}, merged)
+}
+
+// DefaultsFromContext returns the defaults of the context (nil if none)
+func DefaultsFromContext(ctx context.Context) *Defaults {
+	defaults, _ := ctx.Value(defaultsKey{}).(*Defaults)
+	return defaults
+}
+
+// applyContextDefaults will set the context defaults on the message fields, returning the
+// headers to add (raw messages)
+func applyContextDefaults(ctx context.Context, configurationSet *string, tags *map[string]string) map[string]string {
+	defaults := DefaultsFromContext(ctx)
+	if defaults == nil {
+		return nil
+	}
+	if len(*configurationSet) == 0 {
+		*configurationSet = defaults.ConfigurationSet
+	}
+	if len(defaults.Tags) > 0 {
+		merged := make(map[string]string, len(defaults.Tags)+len(*tags))
+		for name, value := range defaults.Tags {
+			merged[name] = value
+		}
+		for name, value := range *tags {
+			merged[name] = value
+		}
+		*tags = merged
+	}
+	return defaults.Headers
+}
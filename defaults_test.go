@@ -0,0 +1,102 @@
+package ses
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestWithDefaults will test merging the context defaults
+func TestWithDefaults(t *testing.T) {
+	if DefaultsFromContext(context.Background()) != nil {
+		t.Errorf("Expected no defaults")
+	}
+	ctx := WithDefaults(context.Background(), &Defaults{
+		ConfigurationSet: "tenant", Tags: map[string]string{"tenant": "acme", "campaign": "none"},
+	})
+	ctx = WithDefaults(ctx, &Defaults{Tags: map[string]string{"campaign": "welcome"}, Headers: map[string]string{"X-Tenant": "acme"}})
+
+	defaults := DefaultsFromContext(ctx)
+	if defaults.ConfigurationSet != "tenant" || defaults.Tags["tenant"] != "acme" || defaults.Tags["campaign"] != "welcome" ||
+		defaults.Headers["X-Tenant"] != "acme" {
+		t.Errorf("Wrong defaults: %+v", defaults)
+	}
+}
+
+// TestConfig_SendContextDefaults will test the context defaults are applied when sending
+func TestConfig_SendContextDefaults(t *testing.T) {
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		ConfigurationSet: "config",
+	}
+	ctx := WithDefaults(context.Background(), &Defaults{
+		ConfigurationSet: "context", Tags: map[string]string{"tenant": "acme", "campaign": "none"},
+		Headers: map[string]string{"X-Tenant": "acme", "X-Existing": "context"},
+	})
+
+	msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s", Tags: map[string]string{"campaign": "welcome"}}
+	if _, err := cfg.Send(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("ConfigurationSetName") != "context" || values.Get("Tags.member.1.Value") != "welcome" ||
+		values.Get("Tags.member.2.Value") != "acme" {
+		t.Errorf("Wrong values: %v", values)
+	}
+	if len(msg.Tags) != 1 {
+		t.Errorf("Expected the message tags to be unchanged: %v", msg.Tags)
+	}
+
+	raw := &RawMessage{Data: []byte("From: from@example.com\r\nX-Existing: message\r\n\r\nbody"), ConfigurationSet: "message"}
+	if _, err := cfg.SendRaw(ctx, raw); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := base64.StdEncoding.DecodeString(values.Get("RawMessage.Data"))
+	parts := splitRaw(data)
+	if parts.get("X-Tenant") != "acme" || parts.get("X-Existing") != "message" || values.Get("ConfigurationSetName") != "message" {
+		t.Errorf("Wrong raw message %s: %v", data, values)
+	}
+
+	// Without defaults the Config applies
+	if _, err := cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("ConfigurationSetName") != "config" {
+		t.Errorf("Wrong configuration set: %s", values.Get("ConfigurationSetName"))
+	}
+}
+
+// TestConfig_SendContextDefaultsInjection will test the context headers can't inject other headers
+func TestConfig_SendContextDefaultsInjection(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	for _, headers := range []map[string]string{
+		{"X-Tenant": "acme\r\nBcc: victim@example.com"},
+		{"X-Tenant\r\nBcc": "victim@example.com"},
+		{"Bcc: victim@example.com\r\nX-Tenant": "acme"},
+	} {
+		ctx := WithDefaults(context.Background(), &Defaults{Headers: headers})
+		raw := &RawMessage{Data: []byte("From: from@example.com\r\nTo: to@example.com\r\n\r\nbody")}
+		if _, err := cfg.SendRaw(ctx, raw); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("expected ErrInvalidHeader got %v", err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected no sends, got %d", calls)
+	}
+}
@@ -0,0 +1,115 @@
+package ses
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// deliverabilityPath is the SES v2 deliverability dashboard path
+const deliverabilityPath = "/v2/email/deliverability-dashboard"
+
+// DomainDeliverabilityCampaign is the inbox placement data for a campaign sent
+// from a domain enrolled in the SES deliverability dashboard (v2 API)
+type DomainDeliverabilityCampaign struct {
+	CampaignID        string    `json:"CampaignId"`
+	DeleteRate        float64   `json:"DeleteRate"`
+	Esps              []string  `json:"Esps"`
+	FirstSeenDateTime time.Time `json:"-"`
+	FromAddress       string    `json:"FromAddress"`
+	ImageURL          string    `json:"ImageUrl"`
+	InboxCount        int64     `json:"InboxCount"`
+	LastSeenDateTime  time.Time `json:"-"`
+	ProjectedVolume   int64     `json:"ProjectedVolume"`
+	ReadDeleteRate    float64   `json:"ReadDeleteRate"`
+	ReadRate          float64   `json:"ReadRate"`
+	SendingIps        []string  `json:"SendingIps"`
+	SpamCount         int64     `json:"SpamCount"`
+	Subject           string    `json:"Subject"`
+}
+
+// UnmarshalJSON will decode the campaign, converting the epoch timestamps
+func (d *DomainDeliverabilityCampaign) UnmarshalJSON(data []byte) error {
+	type campaign DomainDeliverabilityCampaign
+	aux := struct {
+		*campaign
+		FirstSeenDateTime float64 `json:"FirstSeenDateTime"`
+		LastSeenDateTime  float64 `json:"LastSeenDateTime"`
+	}{campaign: (*campaign)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	d.FirstSeenDateTime = epochTime(aux.FirstSeenDateTime)
+	d.LastSeenDateTime = epochTime(aux.LastSeenDateTime)
+	return nil
+}
+
+// epochTime converts the (fractional) epoch seconds used by the v2 API
+func epochTime(seconds float64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+}
+
+// ListDomainDeliverabilityCampaignsInput is the input for ListDomainDeliverabilityCampaigns()
+type ListDomainDeliverabilityCampaignsInput struct {
+	SubscribedDomain string    // Domain enrolled in the dashboard (required)
+	StartDate        time.Time // First day of the range (required)
+	EndDate          time.Time // Last day of the range (required, max 30 days after StartDate)
+	NextToken        string    // Token from a previous page
+	PageSize         int       // Max results per page (optional)
+}
+
+// ListDomainDeliverabilityCampaignsOutput is a page of campaigns
+type ListDomainDeliverabilityCampaignsOutput struct {
+	DomainDeliverabilityCampaigns []*DomainDeliverabilityCampaign `json:"DomainDeliverabilityCampaigns"`
+	NextToken                     string                          `json:"NextToken"`
+}
+
+// GetDomainDeliverabilityCampaign returns the inbox placement data for a single campaign (v2 API)
+func (c *Config) GetDomainDeliverabilityCampaign(ctx context.Context, campaignID string) (*DomainDeliverabilityCampaign, error) {
+	var out struct {
+		DomainDeliverabilityCampaign *DomainDeliverabilityCampaign `json:"DomainDeliverabilityCampaign"`
+	}
+	if err := c.callJSON(ctx, http.MethodGet, deliverabilityPath+"/campaigns/"+url.PathEscape(campaignID), &out); err != nil {
+		return nil, err
+	}
+	return out.DomainDeliverabilityCampaign, nil
+}
+
+// ListDomainDeliverabilityCampaigns returns a page of campaigns for a subscribed domain (v2 API)
+func (c *Config) ListDomainDeliverabilityCampaigns(ctx context.Context,
+	input *ListDomainDeliverabilityCampaignsInput) (*ListDomainDeliverabilityCampaignsOutput, error) {
+
+	query := make(url.Values)
+	query.Set("StartDate", input.StartDate.UTC().Format(time.RFC3339))
+	query.Set("EndDate", input.EndDate.UTC().Format(time.RFC3339))
+	if len(input.NextToken) > 0 {
+		query.Set("NextToken", input.NextToken)
+	}
+	if input.PageSize > 0 {
+		query.Set("PageSize", strconv.Itoa(input.PageSize))
+	}
+
+	out := &ListDomainDeliverabilityCampaignsOutput{}
+	if err := c.callJSON(
+		ctx, http.MethodGet,
+		deliverabilityPath+"/domains/"+url.PathEscape(input.SubscribedDomain)+"/campaigns?"+query.Encode(), out,
+	); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// callJSON fires a v2 API request without a body and decodes the JSON response into out
+func (c *Config) callJSON(ctx context.Context, method, path string, out interface{}) error {
+	result, err := c.sesPost(ctx, &Request{Method: method, Path: path, SigningName: "ses"}, newSendOptions(nil))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(result.Body), out)
+}
@@ -0,0 +1,71 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestConfig_GetDomainDeliverabilityCampaign will test the method GetDomainDeliverabilityCampaign()
+func TestConfig_GetDomainDeliverabilityCampaign(t *testing.T) {
+	var path, method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		method = r.Method
+		_, _ = w.Write([]byte(`{"DomainDeliverabilityCampaign":{"CampaignId":"abc","Subject":"Sale","InboxCount":90,
+			"SpamCount":10,"ReadRate":0.5,"FirstSeenDateTime":1609459200,"SendingIps":["1.2.3.4"]}}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	campaign, err := cfg.GetDomainDeliverabilityCampaign(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodGet || path != "/v2/email/deliverability-dashboard/campaigns/abc" {
+		t.Errorf("Wrong request: %s %s", method, path)
+	}
+	if campaign.CampaignID != "abc" || campaign.InboxCount != 90 || campaign.SpamCount != 10 || campaign.SendingIps[0] != "1.2.3.4" {
+		t.Errorf("Wrong campaign: %+v", campaign)
+	}
+	if !campaign.FirstSeenDateTime.Equal(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong first seen: %s", campaign.FirstSeenDateTime)
+	}
+	if !campaign.LastSeenDateTime.IsZero() {
+		t.Errorf("Expected no last seen, got %s", campaign.LastSeenDateTime)
+	}
+}
+
+// TestConfig_ListDomainDeliverabilityCampaigns will test the method ListDomainDeliverabilityCampaigns()
+func TestConfig_ListDomainDeliverabilityCampaigns(t *testing.T) {
+	var path, startDate, pageSize string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		startDate = r.URL.Query().Get("StartDate")
+		pageSize = r.URL.Query().Get("PageSize")
+		_, _ = w.Write([]byte(`{"DomainDeliverabilityCampaigns":[{"CampaignId":"abc"},{"CampaignId":"def"}],"NextToken":"next"}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	out, err := cfg.ListDomainDeliverabilityCampaigns(context.Background(), &ListDomainDeliverabilityCampaignsInput{
+		SubscribedDomain: "example.com",
+		StartDate:        time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:          time.Date(2021, 1, 30, 0, 0, 0, 0, time.UTC),
+		PageSize:         2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v2/email/deliverability-dashboard/domains/example.com/campaigns" {
+		t.Errorf("Wrong path: %s", path)
+	}
+	if startDate != "2021-01-01T00:00:00Z" || pageSize != "2" {
+		t.Errorf("Wrong query: %s %s", startDate, pageSize)
+	}
+	if len(out.DomainDeliverabilityCampaigns) != 2 || out.NextToken != "next" {
+		t.Errorf("Wrong output: %+v", out)
+	}
+}
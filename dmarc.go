@@ -0,0 +1,127 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrDMARCMisaligned is returned (in strict mode) when neither SPF nor DKIM align with the From domain
+var ErrDMARCMisaligned = errors.New("from domain is not aligned for DMARC")
+
+// defaultMailFromDomain is the MAIL FROM domain SES uses without a custom MAIL FROM
+const defaultMailFromDomain = "amazonses.com"
+
+// AlignmentPolicy checks, before sending, that the From domain aligns with the MAIL FROM
+// domain (SPF) or the DKIM signing domain (d=), as DMARC requires one of them to
+type AlignmentPolicy struct {
+	// MailFromDomain is the custom MAIL FROM domain of the identity (default amazonses.com, never aligned)
+	MailFromDomain string
+
+	// DKIMDomain is the d= domain SES signs with (Easy DKIM: the verified domain identity).
+	// For raw messages a DKIM-Signature header takes precedence
+	DKIMDomain string
+
+	// StrictAlignment requires exact domain matches (DMARC aspf=s / adkim=s) instead of
+	// matching organizational domains
+	StrictAlignment bool
+
+	// Strict blocks misaligned sends with ErrDMARCMisaligned instead of only warning
+	Strict bool
+
+	// OnMisaligned is called with the result of every misaligned send (optional)
+	OnMisaligned func(AlignmentResult)
+}
+
+// AlignmentResult is the DMARC alignment of a message
+type AlignmentResult struct {
+	FromDomain     string
+	MailFromDomain string
+	DKIMDomain     string
+	SPFAligned     bool
+	DKIMAligned    bool
+}
+
+// Aligned returns true if the message can pass DMARC (SPF or DKIM aligned)
+func (r AlignmentResult) Aligned() bool {
+	return r.SPFAligned || r.DKIMAligned
+}
+
+// Check returns the alignment of the From address
+func (p *AlignmentPolicy) Check(from string) (AlignmentResult, error) {
+	return p.check(from, p.DKIMDomain)
+}
+
+// CheckRaw returns the alignment of the From header of the raw message
+func (p *AlignmentPolicy) CheckRaw(m *RawMessage) (AlignmentResult, error) {
+	parts := splitRaw(m.Data)
+	dkimDomain := p.DKIMDomain
+	if signature := parts.get("DKIM-Signature"); len(signature) > 0 {
+		dkimDomain = dkimTag(signature, "d")
+	}
+	return p.check(parts.get("From"), dkimDomain)
+}
+
+// check computes the alignment and applies the warning / strict mode
+func (p *AlignmentPolicy) check(from, dkimDomain string) (AlignmentResult, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return AlignmentResult{}, err
+	}
+	result := AlignmentResult{
+		FromDomain:     strings.ToLower(address.Address[strings.LastIndex(address.Address, "@")+1:]),
+		MailFromDomain: strings.ToLower(p.MailFromDomain),
+		DKIMDomain:     strings.ToLower(dkimDomain),
+	}
+	if len(result.MailFromDomain) == 0 {
+		result.MailFromDomain = defaultMailFromDomain
+	}
+	result.SPFAligned = p.aligned(result.FromDomain, result.MailFromDomain)
+	result.DKIMAligned = p.aligned(result.FromDomain, result.DKIMDomain)
+
+	if result.Aligned() {
+		return result, nil
+	}
+	if p.OnMisaligned != nil {
+		p.OnMisaligned(result)
+	}
+	if p.Strict {
+		return result, fmt.Errorf("%w: from %s, mail from %s, dkim %q",
+			ErrDMARCMisaligned, result.FromDomain, result.MailFromDomain, result.DKIMDomain)
+	}
+	return result, nil
+}
+
+// aligned compares the domains using strict or relaxed alignment
+func (p *AlignmentPolicy) aligned(fromDomain, domain string) bool {
+	if len(domain) == 0 {
+		return false
+	} else if p.StrictAlignment {
+		return fromDomain == domain
+	}
+	return organizationalDomain(fromDomain) == organizationalDomain(domain)
+}
+
+// organizationalDomain returns the organizational domain: the public suffix plus one label
+// (example.co.uk, sap.de), or the domain itself when it has none
+func organizationalDomain(domain string) string {
+	domain = strings.TrimSuffix(domain, ".")
+	organizational, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return domain
+	}
+	return organizational
+}
+
+// dkimTag returns the value of a tag of a DKIM-Signature header
+func dkimTag(signature, name string) string {
+	for _, tag := range strings.Split(signature, ";") {
+		if i := strings.Index(tag, "="); i >= 0 && strings.TrimSpace(tag[:i]) == name {
+			return strings.Join(strings.Fields(tag[i+1:]), "")
+		}
+	}
+	return ""
+}
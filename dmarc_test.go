@@ -0,0 +1,98 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAlignmentPolicy_Check will test the method Check()
+func TestAlignmentPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy AlignmentPolicy
+		from   string
+		spf    bool
+		dkim   bool
+	}{
+		{"default mail from", AlignmentPolicy{}, "a@example.com", false, false},
+		{"relaxed spf", AlignmentPolicy{MailFromDomain: "bounce.example.com"}, "a@example.com", true, false},
+		{"strict spf", AlignmentPolicy{MailFromDomain: "bounce.example.com", StrictAlignment: true}, "a@example.com", false, false},
+		{"relaxed dkim", AlignmentPolicy{DKIMDomain: "example.com"}, "a@mail.example.com", false, true},
+		{"country code", AlignmentPolicy{DKIMDomain: "other.co.uk"}, "a@example.co.uk", false, false},
+		{"short domain", AlignmentPolicy{DKIMDomain: "sap.de"}, "a@mail.sap.de", false, true},
+		{"short domain spf", AlignmentPolicy{MailFromDomain: "bounce.bmw.de"}, "a@bmw.de", true, false},
+		{"other short domain", AlignmentPolicy{DKIMDomain: "bmw.de"}, "a@sap.de", false, false},
+		{"case", AlignmentPolicy{DKIMDomain: "Example.com", StrictAlignment: true}, "a@EXAMPLE.com", false, true},
+	}
+	for _, test := range tests {
+		result, err := test.policy.Check(test.from)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if result.SPFAligned != test.spf || result.DKIMAligned != test.dkim {
+			t.Errorf("%s: wrong result %+v", test.name, result)
+		}
+	}
+}
+
+// TestAlignmentPolicy_Strict will test the warning and strict modes
+func TestAlignmentPolicy_Strict(t *testing.T) {
+	var warned []AlignmentResult
+	policy := &AlignmentPolicy{DKIMDomain: "example.com", OnMisaligned: func(r AlignmentResult) { warned = append(warned, r) }}
+	if _, err := policy.Check("a@other.com"); err != nil {
+		t.Fatalf("expected only a warning: %s", err)
+	}
+	if _, err := policy.Check("a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(warned) != 1 || warned[0].FromDomain != "other.com" {
+		t.Errorf("Wrong warnings: %+v", warned)
+	}
+
+	policy.Strict = true
+	if _, err := policy.Check("a@other.com"); !errors.Is(err, ErrDMARCMisaligned) {
+		t.Errorf("expected ErrDMARCMisaligned got %v", err)
+	}
+}
+
+// TestAlignmentPolicy_CheckRaw will test the DKIM-Signature of a raw message
+func TestAlignmentPolicy_CheckRaw(t *testing.T) {
+	policy := &AlignmentPolicy{DKIMDomain: "other.com"}
+	msg := &RawMessage{Data: []byte("DKIM-Signature: v=1; a=rsa-sha256;\r\n d=example.com; s=sel;\r\nFrom: a@example.com\r\n\r\nbody")}
+	result, err := policy.CheckRaw(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.DKIMAligned || result.DKIMDomain != "example.com" {
+		t.Errorf("Wrong result: %+v", result)
+	}
+}
+
+// TestConfig_SendAlignment will test that a strict policy blocks the send
+func TestConfig_SendAlignment(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		Alignment: &AlignmentPolicy{MailFromDomain: "bounce.example.com", Strict: true},
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "a@other.com", To: []string{to}}); !errors.Is(err, ErrDMARCMisaligned) {
+		t.Errorf("expected ErrDMARCMisaligned got %v", err)
+	}
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: a@other.com\r\n\r\nbody")}); !errors.Is(err, ErrDMARCMisaligned) {
+		t.Errorf("expected ErrDMARCMisaligned got %v", err)
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "a@example.com", To: []string{to}}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call got %d", calls)
+	}
+}
@@ -0,0 +1,194 @@
+package ses
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Event types published by SES (event publishing and identity notifications)
+const (
+	EventTypeBounce           = "Bounce"
+	EventTypeClick            = "Click"
+	EventTypeComplaint        = "Complaint"
+	EventTypeDelivery         = "Delivery"
+	EventTypeDeliveryDelay    = "DeliveryDelay"
+	EventTypeOpen             = "Open"
+	EventTypeReject           = "Reject"
+	EventTypeRenderingFailure = "Rendering Failure"
+	EventTypeSend             = "Send"
+	EventTypeSubscription     = "Subscription"
+)
+
+// ErrUnknownEvent is returned when the payload is not an SES event
+var ErrUnknownEvent = errors.New("payload is not an ses event")
+
+// Event is an SES sending event (bounce, complaint, delivery, etc.) as published
+// to SNS or an event destination
+type Event struct {
+	EventType        string          `json:"eventType"`
+	NotificationType string          `json:"notificationType"`
+	Mail             EventMail       `json:"mail"`
+	Bounce           *EventBounce    `json:"bounce,omitempty"`
+	Complaint        *EventComplaint `json:"complaint,omitempty"`
+	Delivery         *EventDelivery  `json:"delivery,omitempty"`
+	Reject           *EventReject    `json:"reject,omitempty"`
+}
+
+// Type returns the event type (event publishing) or notification type (identity notifications)
+func (e *Event) Type() string {
+	if len(e.EventType) > 0 {
+		return e.EventType
+	}
+	return e.NotificationType
+}
+
+// EventMail is the original message of an event
+type EventMail struct {
+	Timestamp        time.Time           `json:"timestamp"`
+	MessageID        string              `json:"messageId"`
+	Source           string              `json:"source"`
+	SourceArn        string              `json:"sourceArn"`
+	Destination      []string            `json:"destination"`
+	HeadersTruncated bool                `json:"headersTruncated"`
+	Headers          []EventHeader       `json:"headers"`
+	CommonHeaders    EventCommonHeaders  `json:"commonHeaders"`
+	Tags             map[string][]string `json:"tags"`
+}
+
+// Header returns the value of the first original header with the name
+func (m *EventMail) Header(name string) string {
+	name = canonicalKey(name)
+	for _, h := range m.Headers {
+		if canonicalKey(h.Name) == name {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// Tag returns the first value of the message tag with the name
+func (m *EventMail) Tag(name string) string {
+	if values := m.Tags[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// EventHeader is an original header of the message
+type EventHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// EventCommonHeaders are the parsed common headers of the message
+type EventCommonHeaders struct {
+	From      []string `json:"from"`
+	To        []string `json:"to"`
+	MessageID string   `json:"messageId"`
+	Subject   string   `json:"subject"`
+}
+
+// EventBounce is the bounce information of a Bounce event
+type EventBounce struct {
+	BounceType        string           `json:"bounceType"`
+	BounceSubType     string           `json:"bounceSubType"`
+	BouncedRecipients []EventRecipient `json:"bouncedRecipients"`
+	Timestamp         time.Time        `json:"timestamp"`
+	FeedbackID        string           `json:"feedbackId"`
+	ReportingMTA      string           `json:"reportingMTA"`
+}
+
+// EventComplaint is the complaint information of a Complaint event
+type EventComplaint struct {
+	ComplainedRecipients  []EventRecipient `json:"complainedRecipients"`
+	ComplaintFeedbackType string           `json:"complaintFeedbackType"`
+	Timestamp             time.Time        `json:"timestamp"`
+	FeedbackID            string           `json:"feedbackId"`
+}
+
+// EventDelivery is the delivery information of a Delivery event
+type EventDelivery struct {
+	Timestamp            time.Time `json:"timestamp"`
+	ProcessingTimeMillis int64     `json:"processingTimeMillis"`
+	Recipients           []string  `json:"recipients"`
+	SMTPResponse         string    `json:"smtpResponse"`
+	ReportingMTA         string    `json:"reportingMTA"`
+}
+
+// EventReject is the reject information of a Reject event
+type EventReject struct {
+	Reason string `json:"reason"`
+}
+
+// EventRecipient is a bounced or complained recipient
+type EventRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	Action         string `json:"action,omitempty"`
+	Status         string `json:"status,omitempty"`
+	DiagnosticCode string `json:"diagnosticCode,omitempty"`
+}
+
+// snsEnvelope is the SNS notification wrapper (Source is set for EventBridge events)
+type snsEnvelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+	Source  string `json:"source"`
+}
+
+// EventBridgeEvent is an SES event delivered through Amazon EventBridge, the
+// detail is the same Event model as SNS and event destinations
+type EventBridgeEvent struct {
+	Version    string    `json:"version"`
+	ID         string    `json:"id"`
+	DetailType string    `json:"detail-type"` // ie: Email Bounced
+	Source     string    `json:"source"`      // aws.ses
+	Account    string    `json:"account"`
+	Time       time.Time `json:"time"`
+	Region     string    `json:"region"`
+	Resources  []string  `json:"resources"`
+	Detail     *Event    `json:"detail"`
+}
+
+// eventBridgeSource is the source of SES events on EventBridge
+const eventBridgeSource = "aws.ses"
+
+// ParseEventBridgeEvent will decode an SES event delivered through EventBridge
+// (ie: the input of a Lambda function triggered by an EventBridge rule)
+func ParseEventBridgeEvent(data []byte) (*EventBridgeEvent, error) {
+	event := &EventBridgeEvent{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	if event.Source != eventBridgeSource || event.Detail == nil || len(event.Detail.Type()) == 0 {
+		return nil, ErrUnknownEvent
+	}
+	return event, nil
+}
+
+// ParseEvent will decode an SES event, either the raw event or wrapped in an SNS
+// notification or an EventBridge event
+func ParseEvent(data []byte) (*Event, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Type == "Notification" && len(envelope.Message) > 0 {
+		data = []byte(envelope.Message)
+	} else if envelope.Source == eventBridgeSource {
+		event, err := ParseEventBridgeEvent(data)
+		if err != nil {
+			return nil, err
+		}
+		return event.Detail, nil
+	}
+
+	event := &Event{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	if len(event.Type()) == 0 {
+		return nil, ErrUnknownEvent
+	}
+	return event, nil
+}
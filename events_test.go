@@ -0,0 +1,92 @@
+package ses
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// bounceEvent is an SES bounce event (event publishing format)
+const bounceEvent = `{
+  "eventType": "Bounce",
+  "bounce": {
+    "bounceType": "Permanent",
+    "bounceSubType": "General",
+    "bouncedRecipients": [{"emailAddress": "bounce@example.com", "action": "failed", "status": "5.1.1",
+      "diagnosticCode": "smtp; 550 5.1.1 user unknown"}],
+    "timestamp": "2021-01-01T00:00:01.000Z",
+    "feedbackId": "feedback-1"
+  },
+  "mail": {
+    "timestamp": "2021-01-01T00:00:00.000Z",
+    "source": "from@example.com",
+    "messageId": "msg-1",
+    "destination": ["bounce@example.com"],
+    "headers": [{"name": "X-Metadata", "value": "eyJ1c2VyIjoiNDIifQ"}],
+    "commonHeaders": {"from": ["from@example.com"], "to": ["bounce@example.com"], "subject": "hello"},
+    "tags": {"ses:configuration-set": ["set"], "campaign": ["welcome"]}
+  }
+}`
+
+// TestParseEvent will test the method ParseEvent()
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(bounceEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type() != EventTypeBounce || event.Bounce.BounceType != "Permanent" {
+		t.Errorf("Wrong event: %+v", event)
+	}
+	if event.Bounce.BouncedRecipients[0].EmailAddress != "bounce@example.com" || event.Mail.MessageID != "msg-1" {
+		t.Errorf("Wrong bounce: %+v", event.Bounce)
+	}
+	if event.Mail.Tag("campaign") != "welcome" || event.Mail.Header("x-metadata") != "eyJ1c2VyIjoiNDIifQ" {
+		t.Errorf("Wrong tags or headers: %+v", event.Mail)
+	}
+}
+
+// TestParseEvent_SNS will test the method ParseEvent() with an SNS notification
+func TestParseEvent_SNS(t *testing.T) {
+	envelope, _ := json.Marshal(map[string]string{"Type": "Notification", "Message": bounceEvent})
+	event, err := ParseEvent(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Mail.MessageID != "msg-1" {
+		t.Errorf("Wrong event: %+v", event)
+	}
+
+	if _, err = ParseEvent([]byte(`{"Type":"SubscriptionConfirmation"}`)); err != ErrUnknownEvent {
+		t.Errorf("expected %v, got %v", ErrUnknownEvent, err)
+	}
+	if _, err = ParseEvent([]byte(`not json`)); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+// TestParseEventBridgeEvent will test the method ParseEventBridgeEvent()
+func TestParseEventBridgeEvent(t *testing.T) {
+	data := `{"version":"0","id":"id-1","detail-type":"Email Bounced","source":"aws.ses","account":"123456789012",
+		"time":"2021-01-01T00:00:02Z","region":"us-east-1","resources":["arn:aws:ses:us-east-1:123456789012:configuration-set/set"],
+		"detail":` + bounceEvent + `}`
+
+	event, err := ParseEventBridgeEvent([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.DetailType != "Email Bounced" || event.Region != "us-east-1" || event.Detail.Mail.MessageID != "msg-1" {
+		t.Errorf("Wrong event: %+v", event)
+	}
+
+	// The shared decoder returns the same model
+	detail, err := ParseEvent([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Type() != EventTypeBounce || detail.Bounce.BouncedRecipients[0].EmailAddress != "bounce@example.com" {
+		t.Errorf("Wrong detail: %+v", detail)
+	}
+
+	if _, err = ParseEventBridgeEvent([]byte(`{"source":"aws.ec2","detail":{}}`)); err != ErrUnknownEvent {
+		t.Errorf("expected %v, got %v", ErrUnknownEvent, err)
+	}
+}
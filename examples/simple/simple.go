@@ -12,7 +12,7 @@ func main() {
 	to := "success@simulator.amazonses.com"
 
 	// EnvConfig uses the AWS credentials in the environment variables $AWS_ACCESS_KEY_ID and
-	// $AWS_SECRET_KEY.
+	// $AWS_SECRET_ACCESS_KEY (or the legacy $AWS_SECRET_KEY).
 	res, err := ses.EnvConfig.SendEmail(from, []string{to}, []string{}, []string{}, "Example email subject", "Here is the message body.")
 	if err == nil {
 		fmt.Printf("Sent email: %s...\n", res[:32])
@@ -0,0 +1,116 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// From policy errors
+var (
+	ErrFromNotAllowed = errors.New("from address is not in a verified domain")
+	ErrMisleadingFrom = errors.New("from display name contains an email address")
+)
+
+// FromPolicy validates and rewrites the From address centrally, so product teams
+// can't accidentally send from unverified or misleading addresses
+type FromPolicy struct {
+	// Domains are the verified domains (subdomains included) that can be used as From
+	Domains []string
+
+	// DefaultFrom replaces a From address that is not allowed (an error is returned if empty)
+	DefaultFrom string
+
+	// DisplayNames are the display names added when the From has none, by address or domain
+	DisplayNames map[string]string
+
+	// ReplyToOriginal sets the Reply-To to the original From when it is replaced (if no Reply-To is set)
+	ReplyToOriginal bool
+}
+
+// rewrite returns the From to use, and the original address if it was replaced
+func (p *FromPolicy) rewrite(from string) (string, string, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.Contains(address.Name, "@") {
+		return "", "", fmt.Errorf("%w: %s", ErrMisleadingFrom, address.Name)
+	}
+
+	var original string
+	if !p.allowed(address.Address) {
+		if len(p.DefaultFrom) == 0 {
+			return "", "", fmt.Errorf("%w: %s", ErrFromNotAllowed, address.Address)
+		}
+		original = address.String()
+		if address, err = mail.ParseAddress(p.DefaultFrom); err != nil {
+			return "", "", err
+		}
+	}
+
+	if len(address.Name) == 0 {
+		address.Name = p.displayName(address.Address)
+	}
+	return address.String(), original, nil
+}
+
+// allowed returns true if the address is in a verified domain
+func (p *FromPolicy) allowed(address string) bool {
+	for _, domain := range domainCandidates(address) {
+		for _, allowed := range p.Domains {
+			if strings.EqualFold(domain, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// displayName returns the display name for the address (exact address, then domain)
+func (p *FromPolicy) displayName(address string) string {
+	if name, ok := p.DisplayNames[strings.ToLower(address)]; ok {
+		return name
+	}
+	for _, domain := range domainCandidates(address) {
+		if name, ok := p.DisplayNames[domain]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply returns a copy of the message with the From validated and rewritten
+func (p *FromPolicy) Apply(m *Message) (*Message, error) {
+	from, original, err := p.rewrite(m.From)
+	if err != nil {
+		return nil, err
+	}
+	msg := *m
+	msg.From = from
+	if len(original) > 0 && p.ReplyToOriginal && len(msg.ReplyTo) == 0 {
+		msg.ReplyTo = []string{original}
+	}
+	return &msg, nil
+}
+
+// ApplyRaw returns a copy of the raw message with the From header validated and rewritten
+func (p *FromPolicy) ApplyRaw(m *RawMessage) (*RawMessage, error) {
+	parts := splitRaw(m.Data)
+	from, original, err := p.rewrite(parts.get("From"))
+	if err != nil {
+		return nil, err
+	}
+	if err = parts.set("From", from); err != nil {
+		return nil, err
+	}
+	if len(original) > 0 && p.ReplyToOriginal && len(parts.get("Reply-To")) == 0 {
+		if err = parts.set("Reply-To", original); err != nil {
+			return nil, err
+		}
+	}
+	msg := *m
+	msg.Data = parts.bytes()
+	return &msg, nil
+}
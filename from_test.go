@@ -0,0 +1,85 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestFromPolicy_Apply will test the method Apply()
+func TestFromPolicy_Apply(t *testing.T) {
+	policy := &FromPolicy{
+		Domains:         []string{"brand.com"},
+		DefaultFrom:     "notifications@brand.com",
+		DisplayNames:    map[string]string{"brand.com": "Brand", "support@brand.com": "Brand Support"},
+		ReplyToOriginal: true,
+	}
+
+	tests := []struct {
+		from, expectedFrom, expectedReplyTo string
+	}{
+		{"news@brand.com", `"Brand" <news@brand.com>`, ""},
+		{"support@mail.brand.com", `"Brand" <support@mail.brand.com>`, ""},
+		{"support@brand.com", `"Brand Support" <support@brand.com>`, ""},
+		{"Custom <news@brand.com>", `"Custom" <news@brand.com>`, ""},
+		{"Jane <jane@gmail.com>", `"Brand" <notifications@brand.com>`, `"Jane" <jane@gmail.com>`},
+	}
+	for _, test := range tests {
+		msg, err := policy.Apply(&Message{From: test.from, To: []string{to}})
+		if err != nil {
+			t.Fatalf("%s: %s", test.from, err)
+		}
+		if msg.From != test.expectedFrom {
+			t.Errorf("%s: expected from %s got %s", test.from, test.expectedFrom, msg.From)
+		}
+		if len(test.expectedReplyTo) > 0 && (len(msg.ReplyTo) != 1 || msg.ReplyTo[0] != test.expectedReplyTo) {
+			t.Errorf("%s: expected reply-to %s got %v", test.from, test.expectedReplyTo, msg.ReplyTo)
+		}
+	}
+
+	if _, err := policy.Apply(&Message{From: `"ceo@bank.com" <news@brand.com>`}); !errors.Is(err, ErrMisleadingFrom) {
+		t.Errorf("expected %v, got %v", ErrMisleadingFrom, err)
+	}
+	strict := &FromPolicy{Domains: []string{"brand.com"}}
+	if _, err := strict.Apply(&Message{From: "jane@gmail.com"}); !errors.Is(err, ErrFromNotAllowed) {
+		t.Errorf("expected %v, got %v", ErrFromNotAllowed, err)
+	}
+}
+
+// TestFromPolicy_ApplyRaw will test the method ApplyRaw()
+func TestFromPolicy_ApplyRaw(t *testing.T) {
+	policy := &FromPolicy{Domains: []string{"brand.com"}, DefaultFrom: "Brand <notifications@brand.com>", ReplyToOriginal: true}
+	msg, err := policy.ApplyRaw(&RawMessage{Data: []byte("From: jane@gmail.com\nTo: to@example.com\n\nbody")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := splitRaw(msg.Data)
+	if parts.get("From") != `"Brand" <notifications@brand.com>` || parts.get("Reply-To") != "<jane@gmail.com>" {
+		t.Errorf("Wrong headers: %s", msg.Data)
+	}
+}
+
+// TestConfig_SendFromPolicy will test that the FromPolicy is applied when sending
+func TestConfig_SendFromPolicy(t *testing.T) {
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		FromPolicy: &FromPolicy{Domains: []string{"brand.com"}, DefaultFrom: "notifications@brand.com", ReplyToOriginal: true},
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "jane@gmail.com", To: []string{to}, Subject: "subject"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(values.Get("Source"), "notifications@brand.com") || values.Get("ReplyToAddresses.member.1") != "<jane@gmail.com>" {
+		t.Errorf("Wrong values: %v", values)
+	}
+}
@@ -2,4 +2,7 @@ module github.com/mrz1836/go-ses
 
 go 1.15
 
-require github.com/aws/aws-sdk-go v1.40.2
+require (
+	github.com/aws/aws-sdk-go v1.40.2
+	golang.org/x/net v0.0.0-20210614182718-04defd469f4e
+)
@@ -0,0 +1,77 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Headers that SES reads (and removes) from raw messages, the raw-path equivalent of
+// the ConfigurationSetName, Tags, SourceArn, FromArn and ReturnPathArn parameters
+const (
+	HeaderConfigurationSet = "X-SES-CONFIGURATION-SET"
+	HeaderFromArn          = "X-SES-FROM-ARN"
+	HeaderMessageTags      = "X-SES-MESSAGE-TAGS"
+	HeaderReturnPathArn    = "X-SES-RETURN-PATH-ARN"
+	HeaderSourceArn        = "X-SES-SOURCE-ARN"
+)
+
+// ErrInvalidHeader is returned when a header key or value would inject other headers
+var ErrInvalidHeader = errors.New("invalid header: keys can't contain colons or line breaks, values can't contain line breaks")
+
+// ErrInvalidTag is returned when a message tag name or value has invalid characters
+var ErrInvalidTag = errors.New("invalid message tag: only letters, numbers, underscores and dashes are allowed")
+
+// tagPattern are the characters allowed in a message tag name or value
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// SetConfigurationSetHeader will set the X-SES-CONFIGURATION-SET header
+func (m *RawMessage) SetConfigurationSetHeader(name string) error {
+	return m.setHeader(HeaderConfigurationSet, name)
+}
+
+// SetMessageTagsHeader will set the X-SES-MESSAGE-TAGS header (sorted by name)
+func (m *RawMessage) SetMessageTagsHeader(tags map[string]string) error {
+	pairs := make([]string, 0, len(tags))
+	for _, name := range sortedKeys(tags) {
+		if !tagPattern.MatchString(name) || !tagPattern.MatchString(tags[name]) {
+			return fmt.Errorf("%w: %s=%s", ErrInvalidTag, name, tags[name])
+		}
+		pairs = append(pairs, name+"="+tags[name])
+	}
+	return m.setHeader(HeaderMessageTags, strings.Join(pairs, ", "))
+}
+
+// SetSourceArnHeader will set the X-SES-SOURCE-ARN header (sending authorization)
+func (m *RawMessage) SetSourceArnHeader(arn string) error {
+	return m.setHeader(HeaderSourceArn, arn)
+}
+
+// SetFromArnHeader will set the X-SES-FROM-ARN header (sending authorization)
+func (m *RawMessage) SetFromArnHeader(arn string) error {
+	return m.setHeader(HeaderFromArn, arn)
+}
+
+// SetReturnPathArnHeader will set the X-SES-RETURN-PATH-ARN header (sending authorization)
+func (m *RawMessage) SetReturnPathArnHeader(arn string) error {
+	return m.setHeader(HeaderReturnPathArn, arn)
+}
+
+// setHeader will replace (or add) a header of the raw message
+func (m *RawMessage) setHeader(key, value string) error {
+	parts := splitRaw(m.Data)
+	if err := parts.set(key, value); err != nil {
+		return err
+	}
+	m.Data = parts.bytes()
+	return nil
+}
+
+// validateHeader will reject a header key or value that would inject other headers
+func validateHeader(key, value string) error {
+	if len(key) == 0 || strings.ContainsAny(key, ":\r\n") || strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidHeader, key)
+	}
+	return nil
+}
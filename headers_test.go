@@ -0,0 +1,86 @@
+package ses
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRawMessage_SESHeaders will test the X-SES-* header helpers
+func TestRawMessage_SESHeaders(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: from@example.com\r\nTo: to@example.com\r\nX-SES-CONFIGURATION-SET: old\r\n\r\nbody")}
+	for _, err := range []error{
+		msg.SetConfigurationSetHeader("transactional"),
+		msg.SetSourceArnHeader("arn:aws:ses:us-east-1:123:identity/example.com"),
+		msg.SetFromArnHeader("arn:aws:ses:us-east-1:123:identity/from@example.com"),
+		msg.SetReturnPathArnHeader("arn:aws:ses:us-east-1:123:identity/bounce.example.com"),
+		msg.SetMessageTagsHeader(map[string]string{"campaign": "welcome", "app": "web"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	parts := splitRaw(msg.Data)
+	expected := map[string]string{
+		HeaderConfigurationSet: "transactional",
+		HeaderSourceArn:        "arn:aws:ses:us-east-1:123:identity/example.com",
+		HeaderFromArn:          "arn:aws:ses:us-east-1:123:identity/from@example.com",
+		HeaderReturnPathArn:    "arn:aws:ses:us-east-1:123:identity/bounce.example.com",
+		HeaderMessageTags:      "app=web, campaign=welcome",
+	}
+	for key, value := range expected {
+		if parts.get(key) != value {
+			t.Errorf("%s: expected %q got %q", key, value, parts.get(key))
+		}
+	}
+	if strings.Count(string(msg.Data), HeaderConfigurationSet) != 1 {
+		t.Errorf("expected the header to be replaced: %s", msg.Data)
+	}
+	if string(parts.body) != "body" {
+		t.Errorf("Wrong body: %q", parts.body)
+	}
+
+	if err := msg.SetMessageTagsHeader(map[string]string{"bad tag": "x"}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected %v, got %v", ErrInvalidTag, err)
+	}
+}
+
+// TestRawMessage_SESHeadersInjection will test header values can't inject other headers
+func TestRawMessage_SESHeadersInjection(t *testing.T) {
+	data := []byte("From: from@example.com\r\nTo: to@example.com\r\n\r\nbody")
+	msg := &RawMessage{Data: data}
+	for _, err := range []error{
+		msg.SetConfigurationSetHeader("set\r\nBcc: victim@example.com"),
+		msg.SetSourceArnHeader("arn\nBcc: victim@example.com"),
+		msg.SetFromArnHeader("arn\rBcc: victim@example.com"),
+		msg.SetReturnPathArnHeader("arn\r\n\r\nbody"),
+	} {
+		if !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("expected %v, got %v", ErrInvalidHeader, err)
+		}
+	}
+	if string(msg.Data) != string(data) {
+		t.Errorf("expected the message to be unchanged: %q", msg.Data)
+	}
+}
+
+// TestValidateHeader will test the header key and value checks
+func TestValidateHeader(t *testing.T) {
+	tests := []struct {
+		key, value string
+		valid      bool
+	}{
+		{"X-Custom", "value", true},
+		{"X-Custom", "", true},
+		{"", "value", false},
+		{"X-Custom: x", "value", false},
+		{"X-Custom\r\nBcc", "value", false},
+		{"X-Custom", "value\nBcc: victim@example.com", false},
+	}
+	for _, test := range tests {
+		if err := validateHeader(test.key, test.value); (err == nil) != test.valid {
+			t.Errorf("%s Expected valid=%t for %q: %q, got %v", t.Name(), test.valid, test.key, test.value, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package ses
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// iamPolicyVersion is the current IAM policy language version
+const iamPolicyVersion = "2012-10-17"
+
+// Policy is an IAM policy document
+type Policy struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a single statement of an IAM policy
+type PolicyStatement struct {
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// IAMActions returns the (sorted) IAM actions this Config needs to send email, including
+// the calls of the configured guards (see Catalog)
+func (c *Config) IAMActions() []string {
+	sendMethods, guardMethods := c.iamMethods()
+	return c.iamActions(append(sendMethods, guardMethods...))
+}
+
+// iamActions returns the (sorted) IAM actions of the methods for the API version of the Config
+func (c *Config) iamActions(methods []string) []string {
+	version := c.APIVersion
+	if version != APIVersionV2 {
+		version = APIVersionV1
+	}
+	unique := make(map[string]bool)
+	for _, method := range methods {
+		unique[iamAction(LookupOperation(method), version)] = true
+	}
+	actions := make([]string, 0, len(unique))
+	for action := range unique {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// iamAction returns the IAM action of the operation for the API version (operations
+// that are only available in one version are always called using that version)
+func iamAction(operation *Operation, version string) string {
+	for _, v := range []string{version, APIVersionV1, APIVersionV2} {
+		if action, ok := operation.Versions[v]; ok {
+			return action.IAMAction
+		}
+	}
+	return ""
+}
+
+// iamMethods returns the Config methods (Catalog operations) used when sending: the send
+// methods (scoped to the identities) and the calls of the guards (which don't support
+// resource-level permissions)
+func (c *Config) iamMethods() (sendMethods, guardMethods []string) {
+	sendMethods = []string{"Send", "SendRaw"}
+	if c.VerifiedIdentities != nil || c.Sandbox != nil {
+		guardMethods = append(guardMethods, "ListIdentities", "GetIdentityVerificationAttributes")
+	}
+	if c.Sandbox != nil {
+		guardMethods = append(guardMethods, "GetAccount")
+	}
+	return
+}
+
+// IAMPolicy returns the minimal IAM policy for the operations this Config uses,
+// instead of granting "ses:*". Resources are the identity ARNs that can be used
+// (ie: arn:aws:ses:us-east-1:123456789012:identity/example.com), defaults to "*".
+// The ARN of the ConfigurationSet is added for each region and account of the
+// identities, and the calls of the guards get their own statement on "*"
+func (c *Config) IAMPolicy(resources ...string) *Policy {
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	} else if len(c.ConfigurationSet) > 0 {
+		resources = append(append([]string{}, resources...), configurationSetARNs(resources, c.ConfigurationSet)...)
+	}
+	sendMethods, guardMethods := c.iamMethods()
+	policy := &Policy{
+		Version: iamPolicyVersion,
+		Statement: []PolicyStatement{{
+			Effect:   "Allow",
+			Action:   c.iamActions(sendMethods),
+			Resource: resources,
+		}},
+	}
+	if len(guardMethods) > 0 {
+		policy.Statement = append(policy.Statement, PolicyStatement{
+			Effect:   "Allow",
+			Action:   c.iamActions(guardMethods),
+			Resource: []string{"*"},
+		})
+	}
+	return policy
+}
+
+// configurationSetARNs returns the configuration set ARN for each region and account
+// of the SES identity ARNs (arn:aws:ses:<region>:<account>:identity/<identity>)
+func configurationSetARNs(identities []string, name string) []string {
+	var arns []string
+	unique := make(map[string]bool)
+	for _, identity := range identities {
+		i := strings.Index(identity, ":identity/")
+		if !strings.HasPrefix(identity, "arn:aws") || i < 0 {
+			continue
+		}
+		if arn := identity[:i] + ":configuration-set/" + name; !unique[arn] {
+			unique[arn] = true
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
+// JSON returns the policy document as indented JSON (ready for Terraform or the console)
+func (p *Policy) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
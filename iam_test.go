@@ -0,0 +1,76 @@
+package ses
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestConfig_IAMActions will test the method IAMActions()
+func TestConfig_IAMActions(t *testing.T) {
+	v1 := &Config{}
+	if actions := v1.IAMActions(); !reflect.DeepEqual(actions, []string{"ses:SendEmail", "ses:SendRawEmail"}) {
+		t.Errorf("wrong v1 actions: %v", actions)
+	}
+	v2 := &Config{APIVersion: APIVersionV2}
+	if actions := v2.IAMActions(); !reflect.DeepEqual(actions, []string{"ses:SendEmail"}) {
+		t.Errorf("wrong v2 actions: %v", actions)
+	}
+
+	// The identity calls of the guards (v1 only, also called by v2 configs)
+	expected := []string{"ses:GetIdentityVerificationAttributes", "ses:ListIdentities", "ses:SendEmail"}
+	v2.VerifiedIdentities = NewVerifiedIdentityCache(0)
+	if actions := v2.IAMActions(); !reflect.DeepEqual(actions, expected) {
+		t.Errorf("wrong verified identities actions: %v", actions)
+	}
+	v2.Sandbox = NewSandboxGuard(v2.VerifiedIdentities)
+	if actions := v2.IAMActions(); !reflect.DeepEqual(actions, append([]string{"ses:GetAccount"}, expected...)) {
+		t.Errorf("wrong sandbox actions: %v", actions)
+	}
+}
+
+// TestConfig_IAMPolicy will test the method IAMPolicy()
+func TestConfig_IAMPolicy(t *testing.T) {
+	cfg := &Config{}
+	raw, err := cfg.IAMPolicy().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policy Policy
+	if err = json.Unmarshal(raw, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Version != iamPolicyVersion || len(policy.Statement) != 1 {
+		t.Fatalf("wrong policy: %s", raw)
+	}
+	if policy.Statement[0].Effect != "Allow" || policy.Statement[0].Resource[0] != "*" {
+		t.Errorf("wrong statement: %s", raw)
+	}
+
+	arn := "arn:aws:ses:us-east-1:123456789012:identity/example.com"
+	if resource := cfg.IAMPolicy(arn).Statement[0].Resource; len(resource) != 1 || resource[0] != arn {
+		t.Errorf("wrong resource: %v", resource)
+	}
+
+	// The calls of the guards don't support resource-level permissions
+	cfg = &Config{ConfigurationSet: "transactional", Sandbox: NewSandboxGuard(NewVerifiedIdentityCache(0))}
+	policy = *cfg.IAMPolicy(arn, "arn:aws:ses:us-east-1:123456789012:identity/from@example.com",
+		"arn:aws:ses:eu-west-1:123456789012:identity/example.com")
+	expected := []PolicyStatement{{
+		Effect: "Allow",
+		Action: []string{"ses:SendEmail", "ses:SendRawEmail"},
+		Resource: []string{
+			arn, "arn:aws:ses:us-east-1:123456789012:identity/from@example.com",
+			"arn:aws:ses:eu-west-1:123456789012:identity/example.com",
+			"arn:aws:ses:us-east-1:123456789012:configuration-set/transactional",
+			"arn:aws:ses:eu-west-1:123456789012:configuration-set/transactional",
+		},
+	}, {
+		Effect:   "Allow",
+		Action:   []string{"ses:GetAccount", "ses:GetIdentityVerificationAttributes", "ses:ListIdentities"},
+		Resource: []string{"*"},
+	}}
+	if !reflect.DeepEqual(policy.Statement, expected) {
+		t.Errorf("wrong statements: %+v", policy.Statement)
+	}
+}
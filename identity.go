@@ -0,0 +1,99 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"sync"
+)
+
+// ErrNoIdentity is returned when no Config is registered for the From address
+var ErrNoIdentity = errors.New("no sending identity registered for from address")
+
+// IdentityResolver picks the Config (region, configuration set, SourceArn) to use
+// based on the From address, so multi-brand applications don't hand-route every send
+type IdentityResolver struct {
+	fallback   *Config
+	identities map[string]*Config
+	mu         sync.RWMutex
+}
+
+// NewIdentityResolver will return a new resolver, fallback is used when no identity
+// matches (can be nil, then ErrNoIdentity is returned)
+func NewIdentityResolver(fallback *Config) *IdentityResolver {
+	return &IdentityResolver{fallback: fallback, identities: make(map[string]*Config)}
+}
+
+// Register will add an identity, either a full address (user@example.com) or a
+// domain (example.com, which also matches its subdomains)
+func (r *IdentityResolver) Register(identity string, cfg *Config) {
+	r.mu.Lock()
+	r.identities[strings.ToLower(strings.TrimSpace(identity))] = cfg
+	r.mu.Unlock()
+}
+
+// Resolve returns the Config for the From address. The exact address wins over
+// the domain, and the domain wins over its parent domains
+func (r *IdentityResolver) Resolve(from string) (*Config, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(address.Address)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if cfg, ok := r.identities[email]; ok {
+		return cfg, nil
+	}
+	for _, domain := range domainCandidates(email) {
+		if cfg, ok := r.identities[domain]; ok {
+			return cfg, nil
+		}
+	}
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+	return nil, ErrNoIdentity
+}
+
+// Send will resolve the Config using the message From address and send the message
+func (r *IdentityResolver) Send(ctx context.Context, m *Message, opts ...SendOption) (*SendResult, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	cfg, err := r.Resolve(m.From)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Send(ctx, m, opts...)
+}
+
+// SendRaw will resolve the Config using the From header and send the raw message
+func (r *IdentityResolver) SendRaw(ctx context.Context, m *RawMessage, opts ...SendOption) (*SendResult, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	cfg, err := r.Resolve(splitRaw(m.Data).get("From"))
+	if err != nil {
+		return nil, err
+	}
+	return cfg.SendRaw(ctx, m, opts...)
+}
+
+// domainCandidates returns the domain of the address followed by its parent domains
+// (mail.example.com, example.com, com)
+func domainCandidates(address string) (domains []string) {
+	domain := strings.ToLower(address[strings.LastIndex(address, "@")+1:])
+	for len(domain) > 0 {
+		domains = append(domains, domain)
+		i := strings.Index(domain, ".")
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+	return
+}
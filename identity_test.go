@@ -0,0 +1,72 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestIdentityResolver_Resolve will test the method Resolve()
+func TestIdentityResolver_Resolve(t *testing.T) {
+	fallback := &Config{Region: "us-east-1"}
+	brand := &Config{Region: "eu-west-1"}
+	support := &Config{Region: "us-west-2"}
+
+	r := NewIdentityResolver(fallback)
+	r.Register("Brand.com", brand)
+	r.Register("support@brand.com", support)
+
+	tests := []struct {
+		from     string
+		expected *Config
+	}{
+		{"news@brand.com", brand},
+		{"Brand News <news@mail.brand.com>", brand},
+		{"support@BRAND.com", support},
+		{"hello@other.com", fallback},
+	}
+	for _, test := range tests {
+		cfg, err := r.Resolve(test.from)
+		if err != nil {
+			t.Fatalf("%s: %s", test.from, err)
+		}
+		if cfg != test.expected {
+			t.Errorf("%s: expected region %s got %s", test.from, test.expected.Region, cfg.Region)
+		}
+	}
+
+	if _, err := r.Resolve("not an address"); err == nil {
+		t.Errorf("expected an error for an invalid address")
+	}
+	if _, err := NewIdentityResolver(nil).Resolve("hello@other.com"); err != ErrNoIdentity {
+		t.Errorf("expected %v, got %v", ErrNoIdentity, err)
+	}
+}
+
+// TestIdentityResolver_Send will test the method Send()
+func TestIdentityResolver_Send(t *testing.T) {
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	r := NewIdentityResolver(nil)
+	r.Register("brand.com", &Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		ConfigurationSet: "brand-set", SourceArn: "arn:aws:ses:region:123:identity/brand.com",
+	})
+	_, err := r.Send(context.Background(), &Message{From: "news@brand.com", To: []string{to}, Subject: "subject", TextBody: textBody})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("ConfigurationSetName") != "brand-set" {
+		t.Errorf("Wrong configuration set")
+	}
+	if values.Get("SourceArn") != "arn:aws:ses:region:123:identity/brand.com" {
+		t.Errorf("Wrong source arn")
+	}
+}
@@ -0,0 +1,246 @@
+package ses
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// API versions supported by the built-in marshalers
+const (
+	APIVersionV1 = "v1" // Query (form) protocol, the default
+	APIVersionV2 = "v2" // JSON (REST) protocol
+)
+
+// Request is a serialized SES API request that is ready to be signed and sent
+type Request struct {
+	// Method is the HTTP method (POST)
+	Method string
+
+	// Path is appended to the Config.Endpoint (empty for the query protocol)
+	Path string
+
+	// ContentType is the value of the Content-Type header
+	ContentType string
+
+	// SigningName is the service name used in the AWS SigV4 credential scope
+	SigningName string
+
+	// Body is the encoded request body
+	Body []byte
+}
+
+// Marshaler serializes message models into requests for a specific SES protocol
+type Marshaler interface {
+	MarshalMessage(m *Message) (*Request, error)
+	MarshalRawMessage(m *RawMessage) (*Request, error)
+}
+
+// QueryMarshaler builds requests for the SES v1 query (form-encoded) API
+type QueryMarshaler struct {
+	// AccessKeyID is sent along as the AWSAccessKeyId parameter
+	AccessKeyID string
+}
+
+// MarshalMessage will encode a SendEmail action
+func (q *QueryMarshaler) MarshalMessage(m *Message) (*Request, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	data := make(url.Values)
+	data.Add("Action", "SendEmail")
+	data.Add("Source", m.From)
+	addMembers(data, "Destination.ToAddresses.member", m.To)
+	addMembers(data, "Destination.CcAddresses.member", m.Cc)
+	addMembers(data, "Destination.BccAddresses.member", m.Bcc)
+	addMembers(data, "ReplyToAddresses.member", m.ReplyTo)
+	data.Add("Message.Subject.Data", m.Subject)
+	data.Add("Message.Body.Text.Data", m.TextBody)
+	if len(m.HTMLBody) > 0 {
+		data.Add("Message.Body.Html.Data", m.HTMLBody)
+	}
+	addOptional(data, "ConfigurationSetName", m.ConfigurationSet)
+	addOptional(data, "SourceArn", m.SourceArn)
+	addTags(data, m.Tags)
+	data.Add("AWSAccessKeyId", q.AccessKeyID)
+	return q.request(data), nil
+}
+
+// MarshalRawMessage will encode a SendRawEmail action
+func (q *QueryMarshaler) MarshalRawMessage(m *RawMessage) (*Request, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	data := make(url.Values)
+	data.Add("Action", "SendRawEmail")
+	data.Add("RawMessage.Data", base64.StdEncoding.EncodeToString(m.Data))
+	addOptional(data, "ConfigurationSetName", m.ConfigurationSet)
+	addOptional(data, "SourceArn", m.SourceArn)
+	addOptional(data, "FromArn", m.SourceArn)
+	addTags(data, m.Tags)
+	data.Add("AWSAccessKeyId", q.AccessKeyID)
+	return q.request(data), nil
+}
+
+// request wraps the form values into a request
+func (q *QueryMarshaler) request(data url.Values) *Request {
+	return &Request{
+		Method:      http.MethodPost,
+		ContentType: "application/x-www-form-urlencoded",
+		SigningName: "email",
+		Body:        []byte(data.Encode()),
+	}
+}
+
+// addMembers will add a list of values using the query "member.N" notation
+func addMembers(data url.Values, prefix string, values []string) {
+	for i := 0; i < len(values); i++ {
+		data.Add(fmt.Sprintf("%s.%d", prefix, i+1), values[i])
+	}
+}
+
+// addOptional will add the value only if it is set
+func addOptional(data url.Values, key, value string) {
+	if len(value) > 0 {
+		data.Add(key, value)
+	}
+}
+
+// addTags will add the message tags (sorted by name) using the "member.N" notation
+func addTags(data url.Values, tags map[string]string) {
+	for i, name := range sortedKeys(tags) {
+		data.Add(fmt.Sprintf("Tags.member.%d.Name", i+1), name)
+		data.Add(fmt.Sprintf("Tags.member.%d.Value", i+1), tags[name])
+	}
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// JSONMarshaler builds requests for the SES v2 JSON API (SendEmail operation)
+type JSONMarshaler struct{}
+
+// jsonContent is the SES v2 "Content" of a subject or body part
+type jsonContent struct {
+	Data string `json:"Data"`
+}
+
+// jsonDestination is the SES v2 "Destination"
+type jsonDestination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+// jsonBody is the SES v2 "Body" of a simple message
+type jsonBody struct {
+	Text *jsonContent `json:"Text,omitempty"`
+	HTML *jsonContent `json:"Html,omitempty"`
+}
+
+// jsonSimple is the SES v2 "Simple" message
+type jsonSimple struct {
+	Subject jsonContent `json:"Subject"`
+	Body    jsonBody    `json:"Body"`
+}
+
+// jsonRaw is the SES v2 "Raw" message
+type jsonRaw struct {
+	Data []byte `json:"Data"`
+}
+
+// jsonTag is the SES v2 "MessageTag"
+type jsonTag struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// jsonTags converts the tags (sorted by name)
+func jsonTags(tags map[string]string) (list []jsonTag) {
+	for _, name := range sortedKeys(tags) {
+		list = append(list, jsonTag{Name: name, Value: tags[name]})
+	}
+	return
+}
+
+// jsonEmailContent is the SES v2 "Content" of an email
+type jsonEmailContent struct {
+	Simple *jsonSimple `json:"Simple,omitempty"`
+	Raw    *jsonRaw    `json:"Raw,omitempty"`
+}
+
+// jsonSendEmail is the SES v2 SendEmail input
+type jsonSendEmail struct {
+	FromEmailAddress            string           `json:"FromEmailAddress,omitempty"`
+	FromEmailAddressIdentityArn string           `json:"FromEmailAddressIdentityArn,omitempty"`
+	Destination                 *jsonDestination `json:"Destination,omitempty"`
+	ReplyToAddresses            []string         `json:"ReplyToAddresses,omitempty"`
+	Content                     jsonEmailContent `json:"Content"`
+	ConfigurationSetName        string           `json:"ConfigurationSetName,omitempty"`
+	EmailTags                   []jsonTag        `json:"EmailTags,omitempty"`
+}
+
+// MarshalMessage will encode a simple SendEmail operation
+func (j *JSONMarshaler) MarshalMessage(m *Message) (*Request, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	input := &jsonSendEmail{
+		FromEmailAddress:            m.From,
+		FromEmailAddressIdentityArn: m.SourceArn,
+		ConfigurationSetName:        m.ConfigurationSet,
+		EmailTags:                   jsonTags(m.Tags),
+		ReplyToAddresses:            m.ReplyTo,
+		Destination: &jsonDestination{
+			ToAddresses:  m.To,
+			CcAddresses:  m.Cc,
+			BccAddresses: m.Bcc,
+		},
+		Content: jsonEmailContent{Simple: &jsonSimple{
+			Subject: jsonContent{Data: m.Subject},
+			Body:    jsonBody{Text: &jsonContent{Data: m.TextBody}},
+		}},
+	}
+	if len(m.HTMLBody) > 0 {
+		input.Content.Simple.Body.HTML = &jsonContent{Data: m.HTMLBody}
+	}
+	return j.request(input)
+}
+
+// MarshalRawMessage will encode a raw SendEmail operation
+func (j *JSONMarshaler) MarshalRawMessage(m *RawMessage) (*Request, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return j.request(&jsonSendEmail{
+		FromEmailAddressIdentityArn: m.SourceArn,
+		ConfigurationSetName:        m.ConfigurationSet,
+		EmailTags:                   jsonTags(m.Tags),
+		Content:                     jsonEmailContent{Raw: &jsonRaw{Data: m.Data}},
+	})
+}
+
+// request encodes the input into a request
+func (j *JSONMarshaler) request(input *jsonSendEmail) (*Request, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Method:      http.MethodPost,
+		Path:        "/v2/email/outbound-emails",
+		ContentType: "application/json",
+		SigningName: "ses",
+		Body:        body,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package ses
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+// TestQueryMarshaler_MarshalMessage will test the method MarshalMessage()
+func TestQueryMarshaler_MarshalMessage(t *testing.T) {
+	q := &QueryMarshaler{AccessKeyID: "a"}
+	req, err := q.MarshalMessage(&Message{
+		From: "from", To: []string{"to1", "to2"}, Bcc: []string{"bcc"}, Subject: "subject", TextBody: textBody,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.ContentType != "application/x-www-form-urlencoded" || req.SigningName != "email" || len(req.Path) > 0 {
+		t.Errorf("wrong request settings: %+v", req)
+	}
+	values, _ := url.ParseQuery(string(req.Body))
+	if values.Get("Destination.ToAddresses.member.2") != "to2" {
+		t.Errorf("Wrong to address")
+	}
+	if values.Get("Destination.BccAddresses.member.1") != "bcc" {
+		t.Errorf("Wrong bcc address")
+	}
+	if _, ok := values["Message.Body.Html.Data"]; ok {
+		t.Errorf("Unexpected html body")
+	}
+	if values.Get("AWSAccessKeyId") != "a" {
+		t.Errorf("Wrong key")
+	}
+
+	if _, err = q.MarshalMessage(&Message{To: []string{"to"}}); err != ErrMissingFrom {
+		t.Errorf("expected %v, got %v", ErrMissingFrom, err)
+	}
+}
+
+// TestJSONMarshaler_MarshalMessage will test the method MarshalMessage()
+func TestJSONMarshaler_MarshalMessage(t *testing.T) {
+	req, err := (&JSONMarshaler{}).MarshalMessage(&Message{
+		From: "from", To: []string{"to"}, Subject: "subject", TextBody: textBody, HTMLBody: htmlBody,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Path != "/v2/email/outbound-emails" || req.SigningName != "ses" {
+		t.Errorf("wrong request settings: %+v", req)
+	}
+	var input jsonSendEmail
+	if err = json.Unmarshal(req.Body, &input); err != nil {
+		t.Fatal(err)
+	}
+	if input.FromEmailAddress != "from" || input.Destination.ToAddresses[0] != "to" {
+		t.Errorf("Wrong addresses")
+	}
+	if input.Content.Simple.Subject.Data != "subject" || input.Content.Simple.Body.HTML.Data != htmlBody {
+		t.Errorf("Wrong content")
+	}
+}
+
+// TestJSONMarshaler_MarshalRawMessage will test the method MarshalRawMessage()
+func TestJSONMarshaler_MarshalRawMessage(t *testing.T) {
+	req, err := (&JSONMarshaler{}).MarshalRawMessage(&RawMessage{Data: []byte(textBody)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input jsonSendEmail
+	if err = json.Unmarshal(req.Body, &input); err != nil {
+		t.Fatal(err)
+	}
+	if string(input.Content.Raw.Data) != textBody {
+		t.Errorf("Wrong raw data")
+	}
+
+	if _, err = (&JSONMarshaler{}).MarshalRawMessage(&RawMessage{}); err != ErrMissingRawData {
+		t.Errorf("expected %v, got %v", ErrMissingRawData, err)
+	}
+}
@@ -0,0 +1,76 @@
+package ses
+
+import "errors"
+
+// Validation errors returned when a message model is incomplete
+var (
+	ErrMissingFrom       = errors.New("missing from address")
+	ErrMissingRecipients = errors.New("missing recipients: need at least one to, cc or bcc address")
+	ErrMissingRawData    = errors.New("missing raw message data")
+)
+
+// Message is a formatted (non-raw) email with a subject, text and/or html body
+type Message struct {
+	// From is the sender address (must be verified in the AWS control panel)
+	From string
+
+	// To, Cc and Bcc are the recipient addresses
+	To  []string
+	Cc  []string
+	Bcc []string
+
+	// ReplyTo are the reply-to addresses (optional)
+	ReplyTo []string
+
+	// Subject is the subject line of the email
+	Subject string
+
+	// TextBody is the plain text body of the email
+	TextBody string
+
+	// HTMLBody is the html body of the email (optional)
+	HTMLBody string
+
+	// ConfigurationSet is the SES configuration set to use (optional)
+	ConfigurationSet string
+
+	// SourceArn is the ARN of the identity authorized to send for From (optional, sending authorization)
+	SourceArn string
+
+	// Tags are the SES message tags (name/value) published with the sending events
+	Tags map[string]string
+}
+
+// Validate will check that the message has the minimum required fields
+func (m *Message) Validate() error {
+	if len(m.From) == 0 {
+		return ErrMissingFrom
+	}
+	if len(m.To)+len(m.Cc)+len(m.Bcc) == 0 {
+		return ErrMissingRecipients
+	}
+	return nil
+}
+
+// RawMessage is a complete MIME email, headers and body included
+type RawMessage struct {
+	// Data is the raw MIME message
+	Data []byte
+
+	// ConfigurationSet is the SES configuration set to use (optional)
+	ConfigurationSet string
+
+	// SourceArn is the ARN of the identity authorized to send for the From header (optional)
+	SourceArn string
+
+	// Tags are the SES message tags (name/value) published with the sending events
+	Tags map[string]string
+}
+
+// Validate will check that the raw message has data
+func (m *RawMessage) Validate() error {
+	if len(m.Data) == 0 {
+		return ErrMissingRawData
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package ses
+
+import "testing"
+
+// TestMessage_Validate will test the method Validate()
+func TestMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      Message
+		expected error
+	}{
+		{"valid", Message{From: "from", To: []string{"to"}}, nil},
+		{"bcc only", Message{From: "from", Bcc: []string{"bcc"}}, nil},
+		{"missing from", Message{To: []string{"to"}}, ErrMissingFrom},
+		{"missing recipients", Message{From: "from"}, ErrMissingRecipients},
+	}
+	for _, test := range tests {
+		if err := test.msg.Validate(); err != test.expected {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+// TestRawMessage_Validate will test the method Validate()
+func TestRawMessage_Validate(t *testing.T) {
+	if err := (&RawMessage{Data: []byte("raw")}).Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := (&RawMessage{}).Validate(); err != ErrMissingRawData {
+		t.Errorf("expected %v, got %v", ErrMissingRawData, err)
+	}
+}
@@ -0,0 +1,62 @@
+package ses
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MessageIDGenerator generates RFC 5322 Message-ID values (including the angle
+// brackets) for a domain. Implementations must be safe for concurrent use
+type MessageIDGenerator interface {
+	MessageID(domain string) string
+}
+
+// MessageIDGeneratorFunc is a function that implements MessageIDGenerator
+type MessageIDGeneratorFunc func(domain string) string
+
+// MessageID returns a new Message-ID for the domain
+func (f MessageIDGeneratorFunc) MessageID(domain string) string {
+	return f(domain)
+}
+
+// messageIDCounter makes the default ids unique within the process
+var messageIDCounter uint64
+
+// DefaultMessageIDGenerator generates <time.counter.random@domain> ids
+var DefaultMessageIDGenerator MessageIDGenerator = MessageIDGeneratorFunc(func(domain string) string {
+	var random [8]byte
+	_, _ = rand.Read(random[:])
+	return "<" + strconv.FormatInt(time.Now().UnixNano(), 36) + "." +
+		strconv.FormatUint(atomic.AddUint64(&messageIDCounter, 1), 36) + "." +
+		hex.EncodeToString(random[:]) + "@" + domain + ">"
+})
+
+// EnsureMessageID will add a Message-ID header (scoped to the From domain) if the
+// raw message does not have one, and returns the Message-ID. The message is left as-is
+// (returning an empty id) when the From address can't be parsed, SES then generates
+// the id. ErrInvalidHeader is returned if the generated id contains line breaks
+func (m *RawMessage) EnsureMessageID(generator MessageIDGenerator) (string, error) {
+	parts := splitRaw(m.Data)
+	if id := parts.get("Message-ID"); len(id) > 0 {
+		return id, nil
+	}
+	if generator == nil {
+		generator = DefaultMessageIDGenerator
+	}
+
+	address, err := mail.ParseAddress(parts.get("From"))
+	if err != nil {
+		return "", nil
+	}
+	id := generator.MessageID(address.Address[strings.LastIndex(address.Address, "@")+1:])
+	if err := parts.set("Message-ID", id); err != nil {
+		return "", err
+	}
+	m.Data = parts.bytes()
+	return id, nil
+}
@@ -0,0 +1,85 @@
+package ses
+
+import (
+	"errors"
+	"net/mail"
+	"sync"
+	"testing"
+)
+
+// TestRawMessage_EnsureMessageID will test adding the Message-ID header
+func TestRawMessage_EnsureMessageID(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: Sender <from@example.com>\r\nTo: to@example.com\r\n\r\nbody")}
+	id, err := msg.EnsureMessageID(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mail.ParseAddress(id); err != nil {
+		t.Errorf("Invalid Message-ID %q: %s", id, err)
+	}
+	parts := splitRaw(msg.Data)
+	if parts.get("Message-ID") != id {
+		t.Errorf("Expected the header %q got %q", id, parts.get("Message-ID"))
+	}
+	if id[len(id)-len("@example.com>"):] != "@example.com>" {
+		t.Errorf("Expected the From domain: %s", id)
+	}
+	if string(parts.body) != "body" {
+		t.Errorf("Wrong body: %q", parts.body)
+	}
+
+	// Keeps the existing id
+	if again, _ := msg.EnsureMessageID(nil); again != id {
+		t.Errorf("Expected %q got %q", id, again)
+	}
+}
+
+// TestRawMessage_EnsureMessageIDGenerator will test a custom generator and messages without a From domain
+func TestRawMessage_EnsureMessageIDGenerator(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: from@example.com\r\nTo: to@example.com\r\n\r\nbody")}
+	id, err := msg.EnsureMessageID(MessageIDGeneratorFunc(func(domain string) string {
+		return "<fixed@" + domain + ">"
+	}))
+	if err != nil || id != "<fixed@example.com>" {
+		t.Errorf("Wrong id: %s %v", id, err)
+	}
+
+	// No id is made up without a From domain
+	for _, data := range []string{"To: to@example.com\r\n\r\nbody", "From: bob\r\nTo: to@example.com\r\n\r\nbody"} {
+		msg = &RawMessage{Data: []byte(data)}
+		if id, err = msg.EnsureMessageID(nil); err != nil || len(id) > 0 || string(msg.Data) != data {
+			t.Errorf("Expected the message to be unchanged: %q %s %v", msg.Data, id, err)
+		}
+	}
+
+	// Rejects a generated id that would inject headers
+	msg = &RawMessage{Data: []byte("From: from@example.com\r\nTo: to@example.com\r\n\r\nbody")}
+	if _, err = msg.EnsureMessageID(MessageIDGeneratorFunc(func(domain string) string {
+		return "<x@" + domain + ">\r\nBcc: victim@example.com"
+	})); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected %v, got %v", ErrInvalidHeader, err)
+	}
+}
+
+// TestDefaultMessageIDGenerator will test the ids are unique when generated concurrently
+func TestDefaultMessageIDGenerator(t *testing.T) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				id := DefaultMessageIDGenerator.MessageID("example.com")
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("Duplicate id: %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
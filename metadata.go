@@ -0,0 +1,89 @@
+package ses
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Metadata is encoded into a message tag (and a header for raw messages) and
+// decoded back out of the sending events, for exact correlation with application data
+const (
+	MetadataHeader = "X-Metadata"
+	MetadataTag    = "metadata"
+)
+
+// maxTagValueLength is the max length of an SES message tag value
+const maxTagValueLength = 256
+
+// ErrMetadataTooLarge is returned when the encoded metadata does not fit in a message tag
+var ErrMetadataTooLarge = errors.New("encoded metadata is larger than a message tag value")
+
+// EncodeMetadata encodes the key/values into a tag-safe string (base64url of JSON)
+func EncodeMetadata(metadata map[string]string) (string, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	if len(encoded) > maxTagValueLength {
+		return "", fmt.Errorf("%w: %d characters", ErrMetadataTooLarge, len(encoded))
+	}
+	return encoded, nil
+}
+
+// DecodeMetadata decodes the value created by EncodeMetadata()
+func DecodeMetadata(encoded string) (map[string]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]string)
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// SetMetadata will attach the metadata to the message (as the MetadataTag message tag)
+func (m *Message) SetMetadata(metadata map[string]string) error {
+	encoded, err := EncodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	m.Tags = setTag(m.Tags, MetadataTag, encoded)
+	return nil
+}
+
+// SetMetadata will attach the metadata to the raw message (as the MetadataTag message
+// tag and the MetadataHeader header)
+func (m *RawMessage) SetMetadata(metadata map[string]string) error {
+	encoded, err := EncodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	m.Tags = setTag(m.Tags, MetadataTag, encoded)
+	return m.setHeader(MetadataHeader, encoded)
+}
+
+// setTag will set the tag, creating the map if needed
+func setTag(tags map[string]string, name, value string) map[string]string {
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	tags[name] = value
+	return tags
+}
+
+// Metadata returns the metadata attached with SetMetadata() (nil if there is none)
+func (e *Event) Metadata() (map[string]string, error) {
+	encoded := e.Mail.Tag(MetadataTag)
+	if len(encoded) == 0 {
+		encoded = e.Mail.Header(MetadataHeader)
+	}
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	return DecodeMetadata(encoded)
+}
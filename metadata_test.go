@@ -0,0 +1,70 @@
+package ses
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestMessage_SetMetadata will test the metadata round trip through a message tag
+func TestMessage_SetMetadata(t *testing.T) {
+	msg := &Message{From: "from", To: []string{to}, Tags: map[string]string{"campaign": "welcome"}}
+	if err := msg.SetMetadata(map[string]string{"user": "42", "order": "A-1"}); err != nil {
+		t.Fatal(err)
+	}
+	req, err := (&QueryMarshaler{}).MarshalMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _ := url.ParseQuery(string(req.Body))
+	if values.Get("Tags.member.1.Name") != "campaign" || values.Get("Tags.member.2.Name") != MetadataTag {
+		t.Fatalf("Wrong tags: %v", values)
+	}
+
+	// Decode from the published event
+	event := &Event{EventType: EventTypeDelivery, Mail: EventMail{Tags: map[string][]string{
+		MetadataTag: {values.Get("Tags.member.2.Value")},
+	}}}
+	metadata, err := event.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata["user"] != "42" || metadata["order"] != "A-1" {
+		t.Errorf("Wrong metadata: %v", metadata)
+	}
+
+	if err = msg.SetMetadata(map[string]string{"large": strings.Repeat("a", 300)}); !errors.Is(err, ErrMetadataTooLarge) {
+		t.Errorf("expected %v, got %v", ErrMetadataTooLarge, err)
+	}
+}
+
+// TestRawMessage_SetMetadata will test the metadata round trip through a header
+func TestRawMessage_SetMetadata(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: from@example.com\nTo: to@example.com\n\nbody")}
+	if err := msg.SetMetadata(map[string]string{"user": "42"}); err != nil {
+		t.Fatal(err)
+	}
+	encoded := splitRaw(msg.Data).get(MetadataHeader)
+	if len(encoded) == 0 || msg.Tags[MetadataTag] != encoded {
+		t.Fatalf("expected the header and tag to be set: %s", msg.Data)
+	}
+
+	// Decode from the original headers (when tags are not published)
+	event, err := ParseEvent([]byte(bounceEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := event.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata["user"] != "42" {
+		t.Errorf("Wrong metadata: %v", metadata)
+	}
+
+	// No metadata
+	if metadata, err = (&Event{}).Metadata(); err != nil || metadata != nil {
+		t.Errorf("expected no metadata, got %v %v", metadata, err)
+	}
+}
@@ -0,0 +1,128 @@
+package ses
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// rawHeader is a single (possibly folded) header line of a raw message
+type rawHeader struct {
+	key  string // canonical key
+	line string // full line(s) without the trailing line break
+}
+
+// rawParts is a raw message split into its headers and body, keeping the header order
+type rawParts struct {
+	headers []rawHeader
+	body    []byte
+	eol     string
+}
+
+// splitRaw will split a raw message into its headers and body
+func splitRaw(data []byte) *rawParts {
+	parts := &rawParts{eol: "\n"}
+	if bytes.Contains(data, []byte("\r\n")) {
+		parts.eol = "\r\n"
+	}
+
+	// Find the blank line between the headers and body
+	separator := []byte(parts.eol + parts.eol)
+	head := data
+	if i := bytes.Index(data, separator); i >= 0 {
+		head, parts.body = data[:i], data[i+len(separator):]
+	} else if bytes.HasPrefix(data, []byte(parts.eol)) {
+		head, parts.body = nil, data[len(parts.eol):]
+	}
+
+	for _, line := range strings.Split(string(head), parts.eol) {
+		if len(line) == 0 {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(parts.headers) > 0 {
+			last := &parts.headers[len(parts.headers)-1]
+			last.line += parts.eol + line
+			continue
+		}
+		key := line
+		if i := strings.Index(line, ":"); i >= 0 {
+			key = line[:i]
+		}
+		parts.headers = append(parts.headers, rawHeader{key: canonicalKey(key), line: line})
+	}
+	return parts
+}
+
+// canonicalKey returns the canonical format of a header key (case-insensitive compare)
+func canonicalKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// get returns the (unfolded) value of the first header with the key
+func (p *rawParts) get(key string) string {
+	key = canonicalKey(key)
+	for _, h := range p.headers {
+		if h.key == key {
+			value := h.line[strings.Index(h.line, ":")+1:]
+			value = strings.NewReplacer("\r\n", "", "\n", "").Replace(value)
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// set will replace the header (all occurrences) or add it to the top if missing
+func (p *rawParts) set(key, value string) error {
+	if err := validateHeader(key, value); err != nil {
+		return err
+	}
+	line := key + ": " + value
+	canonical := canonicalKey(key)
+	headers := make([]rawHeader, 0, len(p.headers)+1)
+	found := false
+	for _, h := range p.headers {
+		if h.key != canonical {
+			headers = append(headers, h)
+		} else if !found {
+			headers = append(headers, rawHeader{key: canonical, line: line})
+			found = true
+		}
+	}
+	if !found {
+		headers = append([]rawHeader{{key: canonical, line: line}}, headers...)
+	}
+	p.headers = headers
+	return nil
+}
+
+// del will remove the header (all occurrences)
+func (p *rawParts) del(key string) {
+	canonical := canonicalKey(key)
+	headers := p.headers[:0]
+	for _, h := range p.headers {
+		if h.key != canonical {
+			headers = append(headers, h)
+		}
+	}
+	p.headers = headers
+}
+
+// bytes will join the headers and body back into a raw message
+func (p *rawParts) bytes() []byte {
+	var buf bytes.Buffer
+	for _, h := range p.headers {
+		buf.WriteString(h.line)
+		buf.WriteString(p.eol)
+	}
+	buf.WriteString(p.eol)
+	buf.Write(p.body)
+	return buf.Bytes()
+}
+
+// randomBoundary returns a random MIME boundary
+func randomBoundary() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
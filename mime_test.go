@@ -0,0 +1,39 @@
+package ses
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSplitRaw will test the method splitRaw()
+func TestSplitRaw(t *testing.T) {
+	raw := "From: from@example.com\r\nSubject: a long\r\n subject\r\nTo: to@example.com\r\n\r\nbody\r\n"
+	parts := splitRaw([]byte(raw))
+	if parts.eol != "\r\n" || len(parts.headers) != 3 {
+		t.Fatalf("wrong parts: %+v", parts)
+	}
+	if subject := parts.get("SUBJECT"); subject != "a long subject" {
+		t.Errorf("wrong subject: %q", subject)
+	}
+	if string(parts.body) != "body\r\n" {
+		t.Errorf("wrong body: %q", parts.body)
+	}
+	if string(parts.bytes()) != raw {
+		t.Errorf("expected a round trip, got %q", parts.bytes())
+	}
+}
+
+// TestRawParts_Set will test the method set()
+func TestRawParts_Set(t *testing.T) {
+	parts := splitRaw([]byte("From: from@example.com\nX-Test: 1\nX-Test: 2\n\nbody"))
+	parts.set("x-test", "3")
+	parts.set("Message-ID", "<id@example.com>")
+
+	expected := "Message-ID: <id@example.com>\nFrom: from@example.com\nx-test: 3\n\nbody"
+	if raw := string(parts.bytes()); raw != expected {
+		t.Errorf("expected %q got %q", expected, raw)
+	}
+	if strings.Count(string(parts.bytes()), "x-test") != 1 {
+		t.Errorf("expected a single header")
+	}
+}
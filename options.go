@@ -0,0 +1,67 @@
+package ses
+
+import "encoding/json"
+
+// SendOption configures a single send call
+type SendOption func(o *sendOptions)
+
+// sendOptions are the options for a single send call
+type sendOptions struct {
+	endpoint  string
+	rawParams []rawParam
+	region    string
+	stats     bool
+}
+
+// newSendOptions will apply the options
+func newSendOptions(opts []SendOption) *sendOptions {
+	o := &sendOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithStats will collect latency Stats (DNS, connect, TLS, TTFB, total) into the SendResult
+//
+// A failed request still returns the SendResult (with its Stats) alongside the error
+func WithStats() SendOption {
+	return func(o *sendOptions) {
+		o.stats = true
+	}
+}
+
+// WithRegion will send this call from another region (using the standard regional
+// endpoint unless WithEndpoint is also used), ie: EU recipients from eu-west-1
+func WithRegion(region string) SendOption {
+	return func(o *sendOptions) {
+		o.region = region
+	}
+}
+
+// WithEndpoint will send this call to another endpoint
+func WithEndpoint(endpoint string) SendOption {
+	return func(o *sendOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithRawParam will set any SES API parameter on this call (for parameters the library does
+// not support yet). For the v1 query API the key is the form key ("Tags.member.3.Name"), for
+// the v2 JSON API the key is a dotted path ("ListManagementOptions.ContactListName") and the
+// value is sent as a string (see WithRawJSONParam). The parameters set by the library (Action,
+// Source, FromEmailAddress, Destination*, Message*, RawMessage*, Content*) fail the send
+// with ErrInvalidRawParam
+func WithRawParam(key, value string) SendOption {
+	return func(o *sendOptions) {
+		o.rawParams = append(o.rawParams, rawParam{key: key, value: value})
+	}
+}
+
+// WithRawJSONParam will set any v2 JSON API parameter to a structured value (objects, arrays,
+// numbers, booleans) on this call, see WithRawParam
+func WithRawJSONParam(key string, value json.RawMessage) SendOption {
+	return func(o *sendOptions) {
+		o.rawParams = append(o.rawParams, rawParam{key: key, json: value})
+	}
+}
@@ -0,0 +1,127 @@
+package ses
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPGPMissingKey is returned when a recipient has no public key and the policy blocks the send
+var ErrPGPMissingKey = errors.New("missing pgp public key for recipient")
+
+// ErrPGPFormattedMessage is returned when sending a formatted message with a PGPPolicy:
+// only raw messages are encrypted, use SendRaw (with BuildRawMIME)
+var ErrPGPFormattedMessage = errors.New("pgp policy only encrypts raw messages")
+
+// PGPEncrypter encrypts (and optionally signs) message bodies with OpenPGP. This
+// package does not ship an OpenPGP implementation, use an adapter around a library
+// such as github.com/ProtonMail/go-crypto
+type PGPEncrypter interface {
+	// HasKey returns true if there is a registered public key for the recipient address
+	HasKey(recipient string) bool
+
+	// Encrypt returns the ASCII-armored ciphertext of the plaintext for all recipients
+	// (signing it as well if the encrypter holds a private key)
+	Encrypt(plaintext []byte, recipients []string) ([]byte, error)
+}
+
+// PGPFallback is what to do when a recipient has no public key
+type PGPFallback int
+
+// Fallback policies
+const (
+	PGPFallbackBlock           PGPFallback = iota // Do not send (default)
+	PGPFallbackSendUnencrypted                    // Send the message unencrypted
+)
+
+// PGPPolicy encrypts raw messages using PGP/MIME (RFC 3156)
+//
+// A message is encrypted only if every recipient has a key, since all recipients
+// receive the same message. Otherwise the message is blocked if the fallback of any
+// recipient without a key is PGPFallbackBlock, or sent unencrypted
+type PGPPolicy struct {
+	// Encrypter performs the OpenPGP operations (required)
+	Encrypter PGPEncrypter
+
+	// Fallback is the default policy for recipients without a key
+	Fallback PGPFallback
+
+	// Recipients overrides the fallback per recipient address
+	Recipients map[string]PGPFallback
+}
+
+// fallback returns the policy for the recipient
+func (p *PGPPolicy) fallback(recipient string) PGPFallback {
+	if f, ok := p.Recipients[strings.ToLower(recipient)]; ok {
+		return f
+	}
+	return p.Fallback
+}
+
+// EncryptRawMessage returns a copy of the message with the body encrypted (or the
+// message as-is if the fallback policy allows sending unencrypted)
+func (p *PGPPolicy) EncryptRawMessage(m *RawMessage) (*RawMessage, error) {
+	_, to := rawAddresses(m.Data)
+	if len(to) == 0 {
+		return nil, ErrMissingRecipients
+	}
+
+	var missing, blocked []string
+	for _, recipient := range to {
+		if !p.Encrypter.HasKey(recipient) {
+			missing = append(missing, recipient)
+			if p.fallback(recipient) == PGPFallbackBlock {
+				blocked = append(blocked, recipient)
+			}
+		}
+	}
+	if len(blocked) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPGPMissingKey, strings.Join(blocked, ", "))
+	} else if len(missing) > 0 {
+		return m, nil
+	}
+
+	// The encrypted entity is the body with its content headers
+	parts := splitRaw(m.Data)
+	entity := &rawParts{body: parts.body, eol: parts.eol}
+	outer := &rawParts{eol: parts.eol}
+	for _, h := range parts.headers {
+		if strings.HasPrefix(h.key, "content-") {
+			entity.headers = append(entity.headers, h)
+		} else if h.key != "mime-version" {
+			outer.headers = append(outer.headers, h)
+		}
+	}
+	if len(entity.headers) == 0 {
+		entity.headers = []rawHeader{{key: "content-type", line: "Content-Type: text/plain; charset=UTF-8"}}
+	}
+
+	ciphertext, err := p.Encrypter.Encrypt(entity.bytes(), to)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := randomBoundary()
+	eol := parts.eol
+	outer.headers = append(outer.headers,
+		rawHeader{key: "mime-version", line: "MIME-Version: 1.0"},
+		rawHeader{key: "content-type", line: `Content-Type: multipart/encrypted; protocol="application/pgp-encrypted"; boundary="` + boundary + `"`},
+	)
+
+	var body bytes.Buffer
+	body.WriteString("--" + boundary + eol)
+	body.WriteString("Content-Type: application/pgp-encrypted" + eol)
+	body.WriteString("Content-Description: PGP/MIME version identification" + eol + eol)
+	body.WriteString("Version: 1" + eol + eol)
+	body.WriteString("--" + boundary + eol)
+	body.WriteString(`Content-Type: application/octet-stream; name="encrypted.asc"` + eol)
+	body.WriteString(`Content-Disposition: inline; filename="encrypted.asc"` + eol + eol)
+	body.Write(ciphertext)
+	body.WriteString(eol + "--" + boundary + "--" + eol)
+	outer.body = body.Bytes()
+
+	encrypted := *m
+	encrypted.Data = outer.bytes()
+	return &encrypted, nil
+}
@@ -0,0 +1,102 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeEncrypter is a PGPEncrypter for testing
+type fakeEncrypter struct {
+	keys      map[string]bool
+	plaintext string
+}
+
+// HasKey returns true if the recipient has a key
+func (f *fakeEncrypter) HasKey(recipient string) bool {
+	return f.keys[recipient]
+}
+
+// Encrypt will keep the plaintext and return a fake armored message
+func (f *fakeEncrypter) Encrypt(plaintext []byte, _ []string) ([]byte, error) {
+	f.plaintext = string(plaintext)
+	return []byte("-----BEGIN PGP MESSAGE-----\n\nfake\n-----END PGP MESSAGE-----"), nil
+}
+
+const pgpRaw = "From: from@example.com\nTo: a@example.com, b@example.com\nSubject: secret\nMIME-Version: 1.0\n" +
+	"Content-Type: text/plain; charset=UTF-8\n\nthe secret\n"
+
+// TestPGPPolicy_EncryptRawMessage will test the method EncryptRawMessage()
+func TestPGPPolicy_EncryptRawMessage(t *testing.T) {
+	encrypter := &fakeEncrypter{keys: map[string]bool{"a@example.com": true, "b@example.com": true}}
+	policy := &PGPPolicy{Encrypter: encrypter}
+
+	encrypted, err := policy.EncryptRawMessage(&RawMessage{Data: []byte(pgpRaw), ConfigurationSet: "set"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := string(encrypted.Data)
+	if strings.Contains(raw, "the secret") {
+		t.Errorf("plaintext leaked: %s", raw)
+	}
+	if !strings.Contains(raw, `multipart/encrypted; protocol="application/pgp-encrypted"`) || !strings.Contains(raw, "Subject: secret") {
+		t.Errorf("wrong message: %s", raw)
+	}
+	if strings.Count(raw, "MIME-Version") != 1 {
+		t.Errorf("expected a single MIME-Version: %s", raw)
+	}
+	if encrypter.plaintext != "Content-Type: text/plain; charset=UTF-8\n\nthe secret\n" {
+		t.Errorf("wrong plaintext entity: %q", encrypter.plaintext)
+	}
+	if encrypted.ConfigurationSet != "set" {
+		t.Errorf("expected the message settings to be kept")
+	}
+}
+
+// TestPGPPolicy_Fallback will test the fallback policies
+func TestPGPPolicy_Fallback(t *testing.T) {
+	encrypter := &fakeEncrypter{keys: map[string]bool{"a@example.com": true}}
+	msg := &RawMessage{Data: []byte(pgpRaw)}
+
+	// Block by default
+	policy := &PGPPolicy{Encrypter: encrypter}
+	if _, err := policy.EncryptRawMessage(msg); !errors.Is(err, ErrPGPMissingKey) {
+		t.Errorf("expected %v, got %v", ErrPGPMissingKey, err)
+	}
+
+	// Allow unencrypted for the recipient
+	policy.Recipients = map[string]PGPFallback{"b@example.com": PGPFallbackSendUnencrypted}
+	unencrypted, err := policy.EncryptRawMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(unencrypted.Data) != pgpRaw {
+		t.Errorf("expected the message to be sent unencrypted")
+	}
+}
+
+// TestConfig_SendPGPFormatted will test formatted messages are not sent in plaintext with a PGPPolicy
+func TestConfig_SendPGPFormatted(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		PGP: &PGPPolicy{Encrypter: &fakeEncrypter{keys: map[string]bool{"a@example.com": true}}},
+	}
+	if _, err := cfg.SendEmail("from@example.com", []string{"a@example.com"}, nil, nil, "s", "TOPSECRET"); !errors.Is(err, ErrPGPFormattedMessage) {
+		t.Errorf("expected %v, got %v", ErrPGPFormattedMessage, err)
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{"a@example.com"}, TextBody: "TOPSECRET"}); !errors.Is(err, ErrPGPFormattedMessage) {
+		t.Errorf("expected %v, got %v", ErrPGPFormattedMessage, err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no sends, got %d", calls)
+	}
+}
@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Certificate pinning errors
+var (
+	ErrCertificatePinMismatch = errors.New("no certificate matches the pinned public keys")
+	ErrInvalidPin             = errors.New("invalid pin: expected sha256/<base64 sha256 of the public key>")
+)
+
+// pinPrefix is the prefix of the pins (the HPKP / OkHttp format)
+const pinPrefix = "sha256/"
+
+// CertificatePin returns the pin of the certificate public key (sha256/<base64>)
+func CertificatePin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// PinnedTLSConfig returns a copy of the TLS config (can be nil) that also requires a
+// certificate of the verified chain (leaf or CA) to match one of the pins. Pin several
+// keys (ie: the current and next CA) so certificate rotations don't break sending.
+// Session resumption is disabled: VerifyPeerCertificate is not called on resumed sessions
+func PinnedTLSConfig(base *tls.Config, pins ...string) (*tls.Config, error) {
+	if len(pins) == 0 {
+		return nil, ErrInvalidPin
+	}
+	allowed := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pin = strings.TrimSpace(pin)
+		if !strings.HasPrefix(pin, pinPrefix) {
+			pin = pinPrefix + pin
+		}
+		if sum, err := base64.StdEncoding.DecodeString(pin[len(pinPrefix):]); err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPin, pin)
+		}
+		allowed[pin] = true
+	}
+
+	config := &tls.Config{}
+	if base != nil {
+		config = base.Clone()
+	}
+	config.ClientSessionCache = nil
+	verify := config.VerifyPeerCertificate
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if verify != nil {
+			if err := verify(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+		}
+		for _, chain := range verifiedChains {
+			for _, cert := range chain {
+				if allowed[CertificatePin(cert)] {
+					return nil
+				}
+			}
+		}
+		return ErrCertificatePinMismatch
+	}
+	return config, nil
+}
+
+// NewPinnedHTTPClient returns an http client (for Config.HTTPClient) based on the default
+// transport that only connects to endpoints matching the pinned public keys
+func NewPinnedHTTPClient(pins ...string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	config, err := PinnedTLSConfig(transport.TLSClientConfig, pins...)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = config
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,99 @@
+package ses
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPinnedTLSConfig will test the pinned connections
+func TestPinnedTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	pin := CertificatePin(server.Certificate())
+	other := sha256.Sum256([]byte("other key"))
+	otherPin := pinPrefix + base64.StdEncoding.EncodeToString(other[:])
+
+	send := func(pins ...string) error {
+		base := server.Client().Transport.(*http.Transport)
+		config, err := PinnedTLSConfig(base.TLSClientConfig, pins...)
+		if err != nil {
+			return err
+		}
+		transport := base.Clone()
+		transport.TLSClientConfig = config
+		cfg := Config{
+			Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s",
+			HTTPClient: &http.Client{Transport: transport},
+		}
+		_, err = cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}, Subject: "s"})
+		return err
+	}
+
+	// One of the pins matches (rotation) and the prefix is optional
+	if err := send(otherPin, pin[len(pinPrefix):]); err != nil {
+		t.Errorf("expected the pinned server to be accepted: %s", err)
+	}
+	if err := send(otherPin); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("expected ErrCertificatePinMismatch got %v", err)
+	}
+}
+
+// TestPinnedTLSConfig_Resumption will test a session cached by another config can't skip the pins
+func TestPinnedTLSConfig_Resumption(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	other := sha256.Sum256([]byte("other key"))
+	otherPin := pinPrefix + base64.StdEncoding.EncodeToString(other[:])
+
+	base := server.Client().Transport.(*http.Transport)
+	shared := base.TLSClientConfig.Clone()
+	shared.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+	send := func(config *tls.Config) error {
+		transport := base.Clone()
+		transport.TLSClientConfig = config
+		cfg := Config{
+			Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s",
+			HTTPClient: &http.Client{Transport: transport},
+		}
+		_, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}, Subject: "s"})
+		return err
+	}
+
+	// Cache a session without the pins
+	if err := send(shared); err != nil {
+		t.Fatal(err)
+	}
+	pinned, err := PinnedTLSConfig(shared, otherPin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.ClientSessionCache != nil {
+		t.Errorf("expected the session cache to be cleared")
+	}
+	if err = send(pinned); !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Errorf("expected ErrCertificatePinMismatch got %v", err)
+	}
+}
+
+// TestNewPinnedHTTPClient will test the invalid pins
+func TestNewPinnedHTTPClient(t *testing.T) {
+	for _, pins := range [][]string{nil, {"sha256/not base64"}, {"sha256/" + base64.StdEncoding.EncodeToString([]byte("short"))}} {
+		if _, err := NewPinnedHTTPClient(pins...); !errors.Is(err, ErrInvalidPin) {
+			t.Errorf("%v: expected ErrInvalidPin got %v", pins, err)
+		}
+	}
+	sum := sha256.Sum256([]byte("key"))
+	client, err := NewPinnedHTTPClient(base64.StdEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Transport.(*http.Transport).TLSClientConfig.VerifyPeerCertificate == nil {
+		t.Errorf("Expected the pins to be verified")
+	}
+}
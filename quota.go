@@ -0,0 +1,152 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SendQuota is the sending quota of the account (GetSendQuota)
+type SendQuota struct {
+	Max24HourSend   float64 `xml:"GetSendQuotaResult>Max24HourSend"`
+	MaxSendRate     float64 `xml:"GetSendQuotaResult>MaxSendRate"`
+	SentLast24Hours float64 `xml:"GetSendQuotaResult>SentLast24Hours"`
+}
+
+// Usage returns the fraction (0 to 1+) of the 24-hour quota used
+func (q *SendQuota) Usage() float64 {
+	if q.Max24HourSend <= 0 {
+		return 0
+	}
+	return q.SentLast24Hours / q.Max24HourSend
+}
+
+// GetSendQuota returns the sending quota of the account (v1 API)
+func (c *Config) GetSendQuota(ctx context.Context) (*SendQuota, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetSendQuota")
+	quota := &SendQuota{}
+	if err := c.callQuery(ctx, data, quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+// DefaultQuotaThresholds are the usage thresholds watched by default (80% and 95%)
+var DefaultQuotaThresholds = []float64{0.8, 0.95}
+
+// QuotaWatcher alerts when the 24-hour usage crosses thresholds, using a cached
+// GetSendQuota refreshed when older than the ttl, so non-critical mail can be shed
+// before hitting the hard limit
+type QuotaWatcher struct {
+	// OnThreshold is called once each time the usage crosses a threshold upwards (optional)
+	OnThreshold func(threshold float64, quota SendQuota)
+
+	config     *Config
+	ttl        time.Duration
+	thresholds []float64
+	mu         sync.Mutex
+	quota      *SendQuota
+	refreshed  time.Time
+	crossed    map[float64]chan struct{}
+	armed      map[float64]chan struct{}
+}
+
+// NewQuotaWatcher will return a watcher using the Config, the thresholds default to DefaultQuotaThresholds
+func NewQuotaWatcher(cfg *Config, ttl time.Duration, thresholds ...float64) *QuotaWatcher {
+	if len(thresholds) == 0 {
+		thresholds = DefaultQuotaThresholds
+	}
+	thresholds = append([]float64{}, thresholds...)
+	sort.Float64s(thresholds)
+	w := &QuotaWatcher{
+		config: cfg, ttl: ttl, thresholds: thresholds,
+		crossed: make(map[float64]chan struct{}), armed: make(map[float64]chan struct{}),
+	}
+	for _, threshold := range thresholds {
+		w.armed[threshold] = make(chan struct{})
+	}
+	return w
+}
+
+// Crossed returns a channel closed when the usage crosses the threshold (a new channel
+// is returned once the usage is back under it)
+func (w *QuotaWatcher) Crossed(threshold float64) <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ch, ok := w.crossed[threshold]; ok {
+		return ch
+	}
+	if _, ok := w.armed[threshold]; !ok {
+		w.armed[threshold] = make(chan struct{})
+		w.thresholds = append(w.thresholds, threshold)
+		sort.Float64s(w.thresholds)
+	}
+	return w.armed[threshold]
+}
+
+// RecordSend will add sent messages to the cached usage, so the thresholds are crossed
+// between refreshes
+func (w *QuotaWatcher) RecordSend(count int) {
+	w.mu.Lock()
+	if w.quota == nil {
+		w.mu.Unlock()
+		return
+	}
+	w.quota.SentLast24Hours += float64(count)
+	quota, fired := w.evaluate()
+	w.mu.Unlock()
+	w.notify(quota, fired)
+}
+
+// Check returns the quota (refreshed if older than the ttl) and fires the crossed thresholds
+func (w *QuotaWatcher) Check(ctx context.Context) (SendQuota, error) {
+	w.mu.Lock()
+	stale := w.quota == nil || time.Since(w.refreshed) > w.ttl
+	w.mu.Unlock()
+	if stale {
+		quota, err := w.config.GetSendQuota(ctx)
+		if err != nil {
+			return SendQuota{}, err
+		}
+		w.mu.Lock()
+		w.quota, w.refreshed = quota, time.Now()
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	quota, fired := w.evaluate()
+	w.mu.Unlock()
+	w.notify(quota, fired)
+	return quota, nil
+}
+
+// evaluate closes the channels of the thresholds crossed upwards and re-arms the ones
+// back under, returning the quota and the crossed thresholds (must be locked)
+func (w *QuotaWatcher) evaluate() (quota SendQuota, fired []float64) {
+	usage := w.quota.Usage()
+	for _, threshold := range w.thresholds {
+		if ch, armed := w.armed[threshold]; armed && usage >= threshold {
+			close(ch)
+			delete(w.armed, threshold)
+			w.crossed[threshold] = ch
+			fired = append(fired, threshold)
+		} else if !armed && usage < threshold {
+			delete(w.crossed, threshold)
+			w.armed[threshold] = make(chan struct{})
+		}
+	}
+	return *w.quota, fired
+}
+
+// notify calls OnThreshold for the crossed thresholds
+func (w *QuotaWatcher) notify(quota SendQuota, fired []float64) {
+	if w.OnThreshold == nil {
+		return
+	}
+	for _, threshold := range fired {
+		w.OnThreshold(threshold, quota)
+	}
+}
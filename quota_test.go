@@ -0,0 +1,70 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestQuotaWatcher will test the threshold crossings
+func TestQuotaWatcher(t *testing.T) {
+	sent, calls := 700, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`<GetSendQuotaResponse><GetSendQuotaResult><Max24HourSend>1000.0</Max24HourSend>` +
+			`<MaxSendRate>14.0</MaxSendRate><SentLast24Hours>` + strconv.Itoa(sent) + `</SentLast24Hours>` +
+			`</GetSendQuotaResult></GetSendQuotaResponse>`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	var fired []float64
+	watcher := NewQuotaWatcher(cfg, time.Hour)
+	watcher.OnThreshold = func(threshold float64, quota SendQuota) { fired = append(fired, threshold) }
+	warning := watcher.Crossed(0.8)
+
+	quota, err := watcher.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota.Max24HourSend != 1000 || quota.MaxSendRate != 14 || quota.Usage() != 0.7 {
+		t.Errorf("Wrong quota: %+v", quota)
+	}
+	if len(fired) != 0 {
+		t.Errorf("Expected no threshold: %v", fired)
+	}
+
+	// Crossing 80% using the cached quota
+	watcher.RecordSend(150)
+	select {
+	case <-warning:
+	default:
+		t.Errorf("Expected the 80%% channel to be closed")
+	}
+	watcher.RecordSend(10)
+	if len(fired) != 1 || fired[0] != 0.8 || calls != 1 {
+		t.Errorf("Expected a single crossing of 80%% without refreshing: %v (%d calls)", fired, calls)
+	}
+
+	// Crossing 95%, then back under both after a refresh
+	watcher.RecordSend(100)
+	if len(fired) != 2 || fired[1] != 0.95 {
+		t.Errorf("Expected the 95%% crossing: %v", fired)
+	}
+	watcher.ttl, sent = 0, 100
+	if _, err = watcher.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rearmed := watcher.Crossed(0.8)
+	if rearmed == warning {
+		t.Errorf("Expected a new channel once back under the threshold")
+	}
+	select {
+	case <-rearmed:
+		t.Errorf("Expected the new channel to be open")
+	default:
+	}
+}
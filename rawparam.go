@@ -0,0 +1,123 @@
+package ses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidRawParam is returned when a raw parameter can't be set on the request
+var ErrInvalidRawParam = errors.New("invalid raw parameter")
+
+// rawParam is a parameter set with WithRawParam() or WithRawJSONParam()
+type rawParam struct {
+	key   string
+	value string
+	json  json.RawMessage // structured v2 value (WithRawJSONParam)
+}
+
+// managedParams are the parameters set by the library after its guards (From policy,
+// alignment, verified identities, sandbox, PGP...), they can't be overridden
+var managedParams = []string{"Action", "Source", "FromEmailAddress"}
+
+// managedPrefixes are the prefixes of the recipient and content parameters set by the library
+var managedPrefixes = []string{"Destination", "RawMessage", "Content", "Message"}
+
+// validateRawParam returns ErrInvalidRawParam for the parameters managed by the library
+func validateRawParam(key string) error {
+	name := key
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	for _, managed := range managedParams {
+		if name == managed {
+			return fmt.Errorf("%w: %s is set by the library", ErrInvalidRawParam, key)
+		}
+	}
+	for _, prefix := range managedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("%w: %s is set by the library", ErrInvalidRawParam, key)
+		}
+	}
+	return nil
+}
+
+// applyRawParams will set the raw parameters on the encoded request body
+func applyRawParams(req *Request, params []rawParam) error {
+	if len(params) == 0 {
+		return nil
+	}
+	for _, param := range params {
+		if err := validateRawParam(param.key); err != nil {
+			return err
+		}
+	}
+	switch {
+	case strings.HasPrefix(req.ContentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(req.Body))
+		if err != nil {
+			return err
+		}
+		for _, param := range params {
+			if param.json != nil {
+				return fmt.Errorf("%w: %s is a JSON value (v2 API only)", ErrInvalidRawParam, param.key)
+			}
+			values.Set(param.key, param.value)
+		}
+		req.Body = []byte(values.Encode())
+	case strings.HasPrefix(req.ContentType, "application/json"):
+		body := make(map[string]interface{})
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return err
+		}
+		for _, param := range params {
+			value, err := param.jsonValue()
+			if err != nil {
+				return err
+			}
+			if err = setJSONPath(body, param.key, value); err != nil {
+				return err
+			}
+		}
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req.Body = encoded
+	default:
+		return fmt.Errorf("%w: unsupported content type %q", ErrInvalidRawParam, req.ContentType)
+	}
+	return nil
+}
+
+// jsonValue returns the value for the JSON body: the decoded JSON value (WithRawJSONParam) or the string
+func (p rawParam) jsonValue() (interface{}, error) {
+	if p.json == nil {
+		return p.value, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(p.json, &value); err != nil {
+		return nil, fmt.Errorf("%w: %s is not valid JSON: %v", ErrInvalidRawParam, p.key, err)
+	}
+	return value, nil
+}
+
+// setJSONPath sets the value at the dotted path, creating the missing objects
+func setJSONPath(body map[string]interface{}, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := body[key].(map[string]interface{})
+		if !ok {
+			if _, exists := body[key]; exists {
+				return fmt.Errorf("%w: %s is not an object", ErrInvalidRawParam, key)
+			}
+			next = make(map[string]interface{})
+			body[key] = next
+		}
+		body = next
+	}
+	body[keys[len(keys)-1]] = value
+	return nil
+}
@@ -0,0 +1,121 @@
+package ses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestConfig_SendRawParam will test setting raw parameters on the query API
+func TestConfig_SendRawParam(t *testing.T) {
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s"}
+	if _, err := cfg.Send(context.Background(), msg, WithRawParam("NewFeature.Enabled", "true")); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("NewFeature.Enabled") != "true" || values.Get("Source") != "from@example.com" || values.Get("Action") != "SendEmail" {
+		t.Errorf("Wrong values: %v", values)
+	}
+
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: a@example.com\r\n\r\nbody")},
+		WithRawParam("NewFeature.Enabled", "false")); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("NewFeature.Enabled") != "false" || values.Get("Action") != "SendRawEmail" {
+		t.Errorf("Wrong values: %v", values)
+	}
+
+	if _, err := cfg.Send(context.Background(), msg, WithRawJSONParam("NewFeature", json.RawMessage(`{}`))); !errors.Is(err, ErrInvalidRawParam) {
+		t.Errorf("expected ErrInvalidRawParam got %v", err)
+	}
+}
+
+// TestConfig_SendRawParamManaged will test the parameters set by the library can't be overridden
+func TestConfig_SendRawParamManaged(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	for _, version := range []string{APIVersionV1, APIVersionV2} {
+		cfg := Config{
+			Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+			APIVersion: version,
+		}
+		msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s", TextBody: "t"}
+		raw := &RawMessage{Data: []byte("From: a@example.com\r\nTo: b@example.com\r\n\r\nbody")}
+		for _, key := range []string{
+			"Action", "Source", "FromEmailAddress", "Destination.ToAddresses.member.2", "Destinations.member.1",
+			"Destination.BccAddresses", "RawMessage.Data", "Content.Simple.Headers", "Content", "Message.Body.Html.Data",
+		} {
+			if _, err := cfg.Send(context.Background(), msg, WithRawParam(key, "x")); !errors.Is(err, ErrInvalidRawParam) {
+				t.Errorf("%s %s: expected ErrInvalidRawParam got %v", version, key, err)
+			}
+			if _, err := cfg.SendRaw(context.Background(), raw, WithRawJSONParam(key, json.RawMessage(`"x"`))); !errors.Is(err, ErrInvalidRawParam) {
+				t.Errorf("%s %s: expected ErrInvalidRawParam got %v", version, key, err)
+			}
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected no sends, got %d", calls)
+	}
+}
+
+// TestConfig_SendRawParamV2 will test setting raw parameters on the JSON API
+func TestConfig_SendRawParamV2(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		APIVersion: APIVersionV2,
+	}
+	msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s", TextBody: "t"}
+	if _, err := cfg.Send(context.Background(), msg,
+		WithRawJSONParam("ListManagementOptions", json.RawMessage(`{"ContactListName": "newsletter"}`)),
+		WithRawParam("ListManagementOptions.TopicName", "weekly"),
+		WithRawParam("TenantName", "123"),
+		WithRawJSONParam("NewFeature.Weight", json.RawMessage(`5`)),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	options := body["ListManagementOptions"].(map[string]interface{})
+	if options["ContactListName"] != "newsletter" || options["TopicName"] != "weekly" {
+		t.Errorf("Wrong list management options: %v", options)
+	}
+	if body["TenantName"] != "123" || body["NewFeature"].(map[string]interface{})["Weight"] != float64(5) {
+		t.Errorf("Expected a string and a JSON number: %v", body)
+	}
+	if simple := body["Content"].(map[string]interface{})["Simple"].(map[string]interface{}); simple["Subject"].(map[string]interface{})["Data"] != "s" {
+		t.Errorf("Expected the existing content to be kept: %v", simple)
+	}
+
+	if _, err := cfg.Send(context.Background(), msg, WithRawParam("TenantName.Name", "x"), WithRawParam("TenantName", "x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Send(context.Background(), msg, WithRawParam("TenantName", "x"), WithRawParam("TenantName.Name", "x")); !errors.Is(err, ErrInvalidRawParam) {
+		t.Errorf("expected ErrInvalidRawParam got %v", err)
+	}
+	if _, err := cfg.Send(context.Background(), msg, WithRawJSONParam("NewFeature", json.RawMessage(`{`))); !errors.Is(err, ErrInvalidRawParam) {
+		t.Errorf("expected ErrInvalidRawParam got %v", err)
+	}
+}
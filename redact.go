@@ -0,0 +1,73 @@
+package ses
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"strings"
+)
+
+// Redactor obfuscates the recipient addresses written to the audit records and routing
+// decisions, so PII never lands in logs while the records can still be correlated
+// (the same address always gives the same value). Only the address is redacted: it is
+// parsed (dropping any display name) and lowercased first
+type Redactor interface {
+	Redact(address string) string
+}
+
+// RedactorFunc is a function that implements Redactor
+type RedactorFunc func(address string) string
+
+// Redact returns the obfuscated address
+func (f RedactorFunc) Redact(address string) string {
+	return f(address)
+}
+
+// SHA256Redactor is the default Redactor, the sha256 (hex) of the address
+type SHA256Redactor struct{}
+
+// Redact returns the sha256 (hex) of the address
+func (SHA256Redactor) Redact(address string) string {
+	sum := sha256.Sum256([]byte(address))
+	return hex.EncodeToString(sum[:])
+}
+
+// HMACRedactor is the HMAC-SHA256 (hex) of the address with a secret key, which can't be
+// reversed with a dictionary of known addresses (unlike a plain hash)
+type HMACRedactor struct {
+	Key []byte
+}
+
+// Redact returns the HMAC-SHA256 (hex) of the address
+func (h *HMACRedactor) Redact(address string) string {
+	mac := hmac.New(sha256.New, h.Key)
+	_, _ = mac.Write([]byte(address))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// redactor returns the Config Redactor (default SHA256Redactor)
+func (c *Config) redactor() Redactor {
+	if c.Redactor != nil {
+		return c.Redactor
+	}
+	return SHA256Redactor{}
+}
+
+// redactRecipients returns the redacted value of each normalized recipient address
+func redactRecipients(redactor Redactor, to []string) []string {
+	redacted := make([]string, 0, len(to))
+	for _, recipient := range to {
+		redacted = append(redacted, redactor.Redact(normalizeAddress(recipient)))
+	}
+	return redacted
+}
+
+// normalizeAddress returns the lowercased address without the display name (the trimmed
+// value if it can't be parsed), so a recipient redacts the same on every path
+func normalizeAddress(recipient string) string {
+	if address, err := mail.ParseAddress(recipient); err == nil {
+		return strings.ToLower(address.Address)
+	}
+	return strings.ToLower(strings.TrimSpace(recipient))
+}
@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRedactors will test the built-in redactors
+func TestRedactors(t *testing.T) {
+	plain := (SHA256Redactor{}).Redact("to@example.com")
+	if plain != "01ebc948fa4c500ca1dc7b235ea2cadea0e534fe15d7035a1b31ae9d8a00029b" {
+		t.Errorf("Wrong hash: %s", plain)
+	}
+	keyed := &HMACRedactor{Key: []byte("secret")}
+	if keyed.Redact("to@example.com") == plain || len(keyed.Redact("to@example.com")) != 64 {
+		t.Errorf("Expected the HMAC to differ from the plain hash")
+	}
+	if keyed.Redact("to@example.com") != keyed.Redact("to@example.com") {
+		t.Errorf("Expected the same value for the same address (correlation)")
+	}
+	if (&HMACRedactor{Key: []byte("other")}).Redact("to@example.com") == keyed.Redact("to@example.com") {
+		t.Errorf("Expected the value to depend on the key")
+	}
+
+	redacted := redactRecipients(keyed, []string{" To@Example.com ", "to@example.com"})
+	if redacted[0] != redacted[1] {
+		t.Errorf("Expected the addresses to be normalized: %v", redacted)
+	}
+}
+
+// TestConfig_Redactor will test the Redactor is used by the audit records and routing decisions
+func TestConfig_Redactor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	recorder := &auditRecorder{}
+	cfg := &Config{
+		Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		AuditSink: recorder, Redactor: RedactorFunc(func(address string) string { return "user@" + address[strings.Index(address, "@")+1:] }),
+	}
+	router := NewRegionRouter(cfg)
+	var decisions []RouteDecision
+	router.OnRoute = func(decision RouteDecision) { decisions = append(decisions, decision) }
+
+	if _, err := router.Send(context.Background(), &Message{From: "from@example.com", To: []string{"Jane@Example.com"}, Subject: "s"},
+		WithEndpoint(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.records) != 1 || recorder.records[0].RecipientHashes[0] != "user@example.com" {
+		t.Errorf("Wrong audit records: %+v", recorder.records)
+	}
+	if len(decisions) != 1 || decisions[0].RecipientHashes[0] != "user@example.com" {
+		t.Errorf("Wrong decisions: %+v", decisions)
+	}
+}
+
+// TestConfig_RedactorDisplayName will test a recipient redacts the same via Send and SendRaw
+func TestConfig_RedactorDisplayName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	recorder := &auditRecorder{}
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient, AuditSink: recorder}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{"Jane Doe <Jane@Example.com>"}, Subject: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: from@example.com\r\nTo: \"Doe, Jane\" <jane@example.com>\r\n\r\nbody")}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := (SHA256Redactor{}).Redact("jane@example.com")
+	if len(recorder.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recorder.records))
+	}
+	for _, record := range recorder.records {
+		if len(record.RecipientHashes) != 1 || record.RecipientHashes[0] != expected {
+			t.Errorf("%s: expected the hash of the address only, got %v", record.Action, record.RecipientHashes)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxRedirects is the max redirects followed for a single request
+const maxRedirects = 3
+
+// ErrEndpointMoved matches (errors.Is) every EndpointMovedError
+var ErrEndpointMoved = errors.New("endpoint moved")
+
+// EndpointMovedError is returned when SES (or an API gateway in front of it) redirects
+// the request and Config.FollowRedirects is not set
+type EndpointMovedError struct {
+	StatusCode int
+	Location   string
+}
+
+// Error returns the error message
+func (e *EndpointMovedError) Error() string {
+	return fmt.Sprintf("%s: error code %d, new location %s", ErrEndpointMoved, e.StatusCode, e.Location)
+}
+
+// Is returns true for ErrEndpointMoved
+func (e *EndpointMovedError) Is(target error) bool {
+	return target == ErrEndpointMoved
+}
+
+// sesHostPattern matches the regional SES endpoints
+var sesHostPattern = regexp.MustCompile(`^email(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com$`)
+
+// regionFromHost returns the region of a regional SES endpoint host (empty otherwise)
+func regionFromHost(host string) string {
+	if m := sesHostPattern.FindStringSubmatch(host); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// followable returns true if the redirect can be followed: the signed request is only sent
+// again over https, to the same host or to a regional SES endpoint (other amazonaws.com
+// hosts such as S3 buckets or API gateways can be controlled by anyone)
+func followable(from, to *url.URL) bool {
+	if to.Scheme != "https" {
+		return false
+	}
+	if sameHost(from, to) {
+		return true
+	}
+	return (to.Port() == "" || to.Port() == "443") && sesHostPattern.MatchString(strings.ToLower(to.Hostname()))
+}
+
+// sameHost returns true if both URLs have the same host (and port)
+func sameHost(a, b *url.URL) bool {
+	return strings.EqualFold(a.Host, b.Host)
+}
+
+// isRedirect returns true for the redirect status codes that can be followed
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// noRedirects returns the client, or a copy of an *http.Client that does not follow redirects
+// (they need to be signed again, and POST requests are turned into GET requests)
+func noRedirects(client httpInterface) httpInterface {
+	httpClient, ok := client.(*http.Client)
+	if !ok || httpClient.CheckRedirect != nil {
+		return client
+	}
+	withoutRedirects := *httpClient
+	withoutRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &withoutRedirects
+}
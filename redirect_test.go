@@ -0,0 +1,175 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestConfig_SendRedirect will test the redirect handling
+func TestConfig_SendRedirect(t *testing.T) {
+	var action string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/new" {
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+			return
+		}
+		_ = r.ParseForm()
+		action = r.PostForm.Get("Action")
+		_, _ = w.Write([]byte(`<SendEmailResponse><SendEmailResult><MessageId>id</MessageId></SendEmailResult></SendEmailResponse>`))
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: server.Client()}
+	msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s"}
+
+	// Typed error by default
+	_, err := cfg.Send(context.Background(), msg)
+	var movedErr *EndpointMovedError
+	if !errors.As(err, &movedErr) || !errors.Is(err, ErrEndpointMoved) {
+		t.Fatalf("expected an EndpointMovedError got %v", err)
+	}
+	if movedErr.StatusCode != http.StatusPermanentRedirect || movedErr.Location != server.URL+"/new" {
+		t.Errorf("Wrong error: %+v", movedErr)
+	}
+	if len(action) > 0 {
+		t.Errorf("Expected the redirect not to be followed")
+	}
+
+	// Following (the body is posted again)
+	cfg.FollowRedirects = true
+	result, err := cfg.Send(context.Background(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != "SendEmail" || result.MessageID != "id" {
+		t.Errorf("Wrong result %+v (action %s)", result, action)
+	}
+}
+
+// TestConfig_SendRedirectUntrusted will test redirects to other hosts or to http are not followed
+func TestConfig_SendRedirectUntrusted(t *testing.T) {
+	calls := 0
+	other := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer other.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer plain.Close()
+
+	for _, location := range []string{other.URL, plain.URL, "http://email.us-west-2.amazonaws.com"} {
+		gateway := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, location, http.StatusTemporaryRedirect)
+		}))
+		cfg := Config{
+			Endpoint: gateway.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: gateway.Client(),
+			FollowRedirects: true,
+		}
+		if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}}); !errors.Is(err, ErrEndpointMoved) {
+			t.Errorf("%s: expected ErrEndpointMoved got %v", location, err)
+		}
+		gateway.Close()
+	}
+	if calls != 0 {
+		t.Errorf("expected no calls to the untrusted hosts, got %d", calls)
+	}
+}
+
+// TestFollowable will test the method followable()
+func TestFollowable(t *testing.T) {
+	from, _ := url.Parse("https://proxy.example.com/")
+	tests := []struct {
+		location string
+		expected bool
+	}{
+		{"https://proxy.example.com/new", true},
+		{"https://PROXY.example.com/new", true},
+		{"https://email.eu-west-1.amazonaws.com", true},
+		{"https://email-fips.us-east-1.amazonaws.com:443/", true},
+		{"https://email.eu-west-1.amazonaws.com:8443", false},
+		{"https://attacker-bucket.s3.amazonaws.com/x", false},
+		{"https://abc123.execute-api.us-east-1.amazonaws.com", false},
+		{"https://email.attacker.s3.amazonaws.com", false},
+		{"http://proxy.example.com/new", false},
+		{"http://email.eu-west-1.amazonaws.com", false},
+		{"https://attacker.example.com", false},
+		{"https://amazonaws.com.attacker.com", false},
+		{"https://proxy.example.com:8443/new", false},
+	}
+	for _, test := range tests {
+		to, _ := url.Parse(test.location)
+		if output := followable(from, to); output != test.expected {
+			t.Errorf("%s Expected [%t] for [%s] and got [%t]", t.Name(), test.expected, test.location, output)
+		}
+	}
+}
+
+// TestConfig_SendRedirectOtherAWSHost will test redirects to amazonaws.com hosts that are not SES are not followed
+func TestConfig_SendRedirectOtherAWSHost(t *testing.T) {
+	location := "https://attacker-bucket.s3.amazonaws.com/x"
+	gateway := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, location, http.StatusTemporaryRedirect)
+	}))
+	defer gateway.Close()
+
+	// The client would fail the test if it tried to reach the location
+	client := gateway.Client()
+	transport := client.Transport.(*http.Transport).Clone()
+	transport.Proxy = func(r *http.Request) (*url.URL, error) {
+		if r.URL.Host != gateway.Listener.Addr().String() {
+			t.Errorf("Expected no request to %s", r.URL)
+		}
+		return nil, nil
+	}
+	client.Transport = transport
+
+	cfg := Config{
+		Endpoint: gateway.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: client,
+		FollowRedirects: true,
+	}
+	_, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}})
+	var movedErr *EndpointMovedError
+	if !errors.As(err, &movedErr) || movedErr.Location != location {
+		t.Errorf("expected an EndpointMovedError for %s got %v", location, err)
+	}
+}
+
+// TestConfig_SendRedirectLoop will test that redirect loops are not followed forever
+func TestConfig_SendRedirectLoop(t *testing.T) {
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Redirect(w, r, "/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: server.Client(),
+		FollowRedirects: true,
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}}); !errors.Is(err, ErrEndpointMoved) {
+		t.Errorf("expected ErrEndpointMoved got %v", err)
+	}
+	if calls != maxRedirects+1 {
+		t.Errorf("expected %d calls got %d", maxRedirects+1, calls)
+	}
+}
+
+// TestRegionFromHost will test the method regionFromHost()
+func TestRegionFromHost(t *testing.T) {
+	tests := map[string]string{
+		"email.eu-west-1.amazonaws.com":      "eu-west-1",
+		"email-fips.us-east-1.amazonaws.com": "us-east-1",
+		"gateway.example.com":                "",
+	}
+	for host, expected := range tests {
+		if region := regionFromHost(host); region != expected {
+			t.Errorf("%s: expected %q got %q", host, expected, region)
+		}
+	}
+}
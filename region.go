@@ -0,0 +1,49 @@
+package ses
+
+// RegionEndpoint returns the standard SES endpoint for the region
+func RegionEndpoint(region string) string {
+	return "https://email." + region + ".amazonaws.com"
+}
+
+// WithRegion returns a copy of the Config that sends from the region (using the
+// standard regional endpoint), without modifying the original Config
+func (c *Config) WithRegion(region string) *Config {
+	return c.Clone(OverrideRegion(region))
+}
+
+// WithEndpoint returns a copy of the Config that sends to the endpoint
+func (c *Config) WithEndpoint(endpoint string) *Config {
+	return c.Clone(OverrideEndpoint(endpoint))
+}
+
+// copy returns a copy of the Config (all exported fields, with the current credentials)
+func (c *Config) copy() *Config {
+	creds := c.credentials()
+	return &Config{
+		Endpoint:           c.Endpoint,
+		Region:             c.Region,
+		AccessKeyID:        creds.AccessKeyID,
+		SecretAccessKey:    creds.SecretAccessKey,
+		SessionToken:       creds.SessionToken,
+		HTTPClient:         c.HTTPClient,
+		APIVersion:         c.APIVersion,
+		Marshaler:          c.Marshaler,
+		ConfigurationSet:   c.ConfigurationSet,
+		SourceArn:          c.SourceArn,
+		AuditSink:          c.AuditSink,
+		Redactor:           c.Redactor,
+		PGP:                c.PGP,
+		FromPolicy:         c.FromPolicy,
+		Alignment:          c.Alignment,
+		VerifiedIdentities: c.VerifiedIdentities,
+		Sandbox:            c.Sandbox,
+		BodyTransformers:   c.BodyTransformers,
+		AttachmentScanner:  c.AttachmentScanner,
+		HTMLSizeBudget:     c.HTMLSizeBudget,
+		SigningHost:        c.SigningHost,
+		FollowRedirects:    c.FollowRedirects,
+		SigningAlgorithm:   c.SigningAlgorithm,
+		SigningRegionSet:   c.SigningRegionSet,
+		MessageIDGenerator: c.MessageIDGenerator,
+	}
+}
@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestConfig_WithRegion will test the method WithRegion()
+func TestConfig_WithRegion(t *testing.T) {
+	cfg := &Config{Endpoint: "https://email.us-east-1.amazonaws.com", Region: "us-east-1", ConfigurationSet: "set"}
+	eu := cfg.WithRegion("eu-west-1")
+	if eu.Region != "eu-west-1" || eu.Endpoint != "https://email.eu-west-1.amazonaws.com" || eu.ConfigurationSet != "set" {
+		t.Errorf("Wrong config: %+v", eu)
+	}
+	if cfg.Region != "us-east-1" || cfg.Endpoint != "https://email.us-east-1.amazonaws.com" {
+		t.Errorf("expected the original config to be unchanged")
+	}
+	if proxy := cfg.WithEndpoint("https://proxy"); proxy.Endpoint != "https://proxy" || proxy.Region != "us-east-1" {
+		t.Errorf("Wrong config: %+v", proxy)
+	}
+}
+
+// TestConfig_Copy will test that copy() keeps every exported field
+func TestConfig_Copy(t *testing.T) {
+	cfg := &Config{}
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(v.Type().Field(i).Name)
+		case reflect.Ptr:
+			field.Set(reflect.New(field.Type().Elem()))
+		case reflect.Slice:
+			field.Set(reflect.MakeSlice(field.Type(), 1, 1))
+		}
+	}
+	cfg.HTTPClient = http.DefaultClient
+	cfg.Marshaler = &JSONMarshaler{}
+	cfg.AuditSink = &auditRecorder{}
+
+	cp := cfg.copy()
+	c := reflect.ValueOf(cp).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).CanSet() {
+			continue
+		}
+		if !reflect.DeepEqual(v.Field(i).Interface(), c.Field(i).Interface()) {
+			t.Errorf("field %s was not copied", v.Type().Field(i).Name)
+		}
+	}
+}
+
+// TestConfig_SendWithRegion will test the options WithRegion() and WithEndpoint()
+func TestConfig_SendWithRegion(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: "http://invalid.localhost", Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	msg := &Message{From: "from", To: []string{to}, Subject: "region test", TextBody: textBody}
+	if _, err := cfg.Send(context.Background(), msg, WithRegion("eu-west-1"), WithEndpoint(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=a/%s/eu-west-1/email/aws4_request", time.Now().UTC().Format("20060102"))
+	if !strings.HasPrefix(auth, expected) {
+		t.Errorf("Wrong signature: expected: %s got %s", expected, auth)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Errorf("expected the config to be unchanged")
+	}
+}
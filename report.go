@@ -0,0 +1,204 @@
+package ses
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// Report types (the report-type parameter of multipart/report)
+const (
+	ReportTypeDeliveryStatus          = "delivery-status"
+	ReportTypeDispositionNotification = "disposition-notification"
+)
+
+// ErrNotReport is returned when the message is not a multipart/report
+var ErrNotReport = errors.New("message is not a multipart/report")
+
+// Report is a delivery status notification (RFC 3464) or a message disposition
+// notification / read receipt (RFC 3798) received via SES inbound
+type Report struct {
+	// Type is the report type (ReportTypeDeliveryStatus or ReportTypeDispositionNotification)
+	Type string
+
+	// Text is the human-readable part of the report
+	Text string
+
+	// DeliveryStatus is set for delivery status notifications
+	DeliveryStatus *DeliveryStatus
+
+	// Disposition is set for message disposition notifications
+	Disposition *Disposition
+
+	// OriginalHeaders are the headers of the original message (if returned)
+	OriginalHeaders mail.Header
+}
+
+// DeliveryStatus is the machine-readable part of a DSN (RFC 3464)
+type DeliveryStatus struct {
+	ReportingMTA       string
+	OriginalEnvelopeID string
+	ArrivalDate        string
+	Recipients         []DeliveryStatusRecipient
+}
+
+// DeliveryStatusRecipient is the per-recipient status of a DSN
+type DeliveryStatusRecipient struct {
+	FinalRecipient    string
+	OriginalRecipient string
+	Action            string // failed, delayed, delivered, relayed or expanded
+	Status            string // ie: 5.1.1
+	RemoteMTA         string
+	DiagnosticCode    string
+	LastAttemptDate   string
+}
+
+// Permanent returns true if the status is a permanent failure (5.X.X)
+func (d *DeliveryStatusRecipient) Permanent() bool {
+	return strings.HasPrefix(d.Status, "5.")
+}
+
+// Disposition is the machine-readable part of an MDN (RFC 3798)
+type Disposition struct {
+	ReportingUA       string
+	OriginalRecipient string
+	FinalRecipient    string
+	OriginalMessageID string
+	Disposition       string // ie: manual-action/MDN-sent-manually; displayed
+}
+
+// Type returns the disposition type (displayed, deleted, etc.)
+func (d *Disposition) Type() string {
+	value := d.Disposition
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = value[i+1:]
+	}
+	if i := strings.Index(value, "/"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// ParseReport will parse a DSN or MDN message
+func ParseReport(r io.Reader) (*Report, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, ErrNotReport
+	}
+
+	report := &Report{Type: strings.ToLower(params["report-type"])}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		var data []byte
+		if data, err = ioutil.ReadAll(part); err != nil {
+			return nil, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch strings.ToLower(partType) {
+		case "text/plain":
+			if len(report.Text) == 0 {
+				report.Text = string(data)
+			}
+		case "message/delivery-status", "message/global-delivery-status":
+			if report.DeliveryStatus, err = parseDeliveryStatus(data); err != nil {
+				return nil, err
+			}
+		case "message/disposition-notification", "message/global-disposition-notification":
+			if report.Disposition, err = parseDisposition(data); err != nil {
+				return nil, err
+			}
+		case "message/rfc822", "text/rfc822-headers", "message/global", "message/global-headers":
+			if original, err := mail.ReadMessage(bytes.NewReader(append(data, '\n', '\n'))); err == nil {
+				report.OriginalHeaders = original.Header
+			}
+		}
+	}
+	return report, nil
+}
+
+// readFieldBlocks reads the blank-line separated blocks of "Name: value" fields
+func readFieldBlocks(data []byte) (blocks []textproto.MIMEHeader, err error) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		block, err := reader.ReadMIMEHeader()
+		if len(block) > 0 {
+			blocks = append(blocks, block)
+		}
+		if err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseDeliveryStatus parses the per-message and per-recipient fields of a DSN
+func parseDeliveryStatus(data []byte) (*DeliveryStatus, error) {
+	blocks, err := readFieldBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+	status := &DeliveryStatus{}
+	for i, block := range blocks {
+		if i == 0 && len(block.Get("Final-Recipient")) == 0 {
+			status.ReportingMTA = fieldValue(block.Get("Reporting-MTA"))
+			status.OriginalEnvelopeID = block.Get("Original-Envelope-Id")
+			status.ArrivalDate = block.Get("Arrival-Date")
+			continue
+		}
+		status.Recipients = append(status.Recipients, DeliveryStatusRecipient{
+			FinalRecipient:    fieldValue(block.Get("Final-Recipient")),
+			OriginalRecipient: fieldValue(block.Get("Original-Recipient")),
+			Action:            strings.ToLower(block.Get("Action")),
+			Status:            block.Get("Status"),
+			RemoteMTA:         fieldValue(block.Get("Remote-MTA")),
+			DiagnosticCode:    block.Get("Diagnostic-Code"),
+			LastAttemptDate:   block.Get("Last-Attempt-Date"),
+		})
+	}
+	return status, nil
+}
+
+// parseDisposition parses the fields of an MDN
+func parseDisposition(data []byte) (*Disposition, error) {
+	blocks, err := readFieldBlocks(data)
+	if err != nil {
+		return nil, err
+	} else if len(blocks) == 0 {
+		return &Disposition{}, nil
+	}
+	block := blocks[0]
+	return &Disposition{
+		ReportingUA:       block.Get("Reporting-UA"),
+		OriginalRecipient: fieldValue(block.Get("Original-Recipient")),
+		FinalRecipient:    fieldValue(block.Get("Final-Recipient")),
+		OriginalMessageID: block.Get("Original-Message-ID"),
+		Disposition:       block.Get("Disposition"),
+	}, nil
+}
+
+// fieldValue strips the type prefix of a typed field (ie: "rfc822; user@example.com")
+func fieldValue(value string) string {
+	if i := strings.Index(value, ";"); i >= 0 {
+		return strings.TrimSpace(value[i+1:])
+	}
+	return strings.TrimSpace(value)
+}
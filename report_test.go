@@ -0,0 +1,120 @@
+package ses
+
+import (
+	"strings"
+	"testing"
+)
+
+// dsnMessage is a delivery status notification (RFC 3464)
+const dsnMessage = `From: MAILER-DAEMON@example.com
+To: from@example.com
+Subject: Delivery Status Notification (Failure)
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="dsn"
+
+--dsn
+Content-Type: text/plain
+
+An error occurred while trying to deliver the mail.
+
+--dsn
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mta.example.com
+Arrival-Date: Fri, 1 Jan 2021 00:00:00 +0000
+
+Final-Recipient: rfc822; unknown@example.com
+Action: failed
+Status: 5.1.1
+Remote-MTA: dns; mx.example.com
+Diagnostic-Code: smtp; 550 5.1.1 user unknown
+
+Final-Recipient: rfc822; full@example.com
+Action: delayed
+Status: 4.2.2
+
+--dsn
+Content-Type: text/rfc822-headers
+
+From: from@example.com
+To: unknown@example.com
+Message-ID: <original@example.com>
+Subject: hello
+
+--dsn--
+`
+
+// mdnMessage is a read receipt (RFC 3798)
+const mdnMessage = `From: to@example.com
+To: from@example.com
+Subject: Read: hello
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=disposition-notification; boundary="mdn"
+
+--mdn
+Content-Type: text/plain
+
+Your message was displayed.
+
+--mdn
+Content-Type: message/disposition-notification
+
+Reporting-UA: mail.example.com; Mail Client
+Final-Recipient: rfc822; to@example.com
+Original-Message-ID: <original@example.com>
+Disposition: manual-action/MDN-sent-manually; displayed
+
+--mdn--
+`
+
+// TestParseReport_DSN will test the method ParseReport() with a DSN
+func TestParseReport_DSN(t *testing.T) {
+	report, err := ParseReport(strings.NewReader(dsnMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Type != ReportTypeDeliveryStatus || !strings.Contains(report.Text, "error occurred") {
+		t.Errorf("Wrong report: %+v", report)
+	}
+	status := report.DeliveryStatus
+	if status == nil || status.ReportingMTA != "mta.example.com" || len(status.Recipients) != 2 {
+		t.Fatalf("Wrong delivery status: %+v", status)
+	}
+	failed := status.Recipients[0]
+	if failed.FinalRecipient != "unknown@example.com" || failed.Action != "failed" || !failed.Permanent() {
+		t.Errorf("Wrong recipient: %+v", failed)
+	}
+	if failed.DiagnosticCode != "smtp; 550 5.1.1 user unknown" || failed.RemoteMTA != "mx.example.com" {
+		t.Errorf("Wrong recipient: %+v", failed)
+	}
+	if status.Recipients[1].Permanent() {
+		t.Errorf("expected a transient failure")
+	}
+	if report.OriginalHeaders.Get("Message-ID") != "<original@example.com>" {
+		t.Errorf("Wrong original headers: %v", report.OriginalHeaders)
+	}
+}
+
+// TestParseReport_MDN will test the method ParseReport() with an MDN
+func TestParseReport_MDN(t *testing.T) {
+	report, err := ParseReport(strings.NewReader(mdnMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Type != ReportTypeDispositionNotification || report.Disposition == nil {
+		t.Fatalf("Wrong report: %+v", report)
+	}
+	if report.Disposition.FinalRecipient != "to@example.com" || report.Disposition.OriginalMessageID != "<original@example.com>" {
+		t.Errorf("Wrong disposition: %+v", report.Disposition)
+	}
+	if report.Disposition.Type() != "displayed" {
+		t.Errorf("Wrong disposition type: %s", report.Disposition.Type())
+	}
+}
+
+// TestParseReport_NotReport will test the method ParseReport() with a regular message
+func TestParseReport_NotReport(t *testing.T) {
+	if _, err := ParseReport(strings.NewReader("From: a@example.com\nContent-Type: text/plain\n\nhello")); err != ErrNotReport {
+		t.Errorf("expected %v, got %v", ErrNotReport, err)
+	}
+}
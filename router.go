@@ -0,0 +1,200 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRegionConflict is returned when the recipients of a raw message require different regions
+var ErrRegionConflict = errors.New("recipients require different sending regions")
+
+// defaultTenantTag is the message tag that holds the tenant
+const defaultTenantTag = "tenant"
+
+// RegionSendError is returned by RegionRouter.Send when the part of a region fails. The
+// parts are sent in order and the send stops at the first failure: the regions before
+// Region were sent (their results are returned with the error), the Unsent regions were not
+type RegionSendError struct {
+	Region string   // region that failed
+	Unsent []string // regions after Region that were not attempted
+	Err    error
+}
+
+// Error returns the failed region and the error
+func (e *RegionSendError) Error() string {
+	return fmt.Sprintf("region %s: %s", e.Region, e.Err)
+}
+
+// Unwrap returns the error of the failed region
+func (e *RegionSendError) Unwrap() error {
+	return e.Err
+}
+
+// RouteDecision is the audit trail of a routing decision
+type RouteDecision struct {
+	Time            time.Time
+	Region          string
+	Reason          string // tenant:<id>, domain:<domain> or default
+	RecipientHashes []string
+}
+
+// RegionRouter maps recipient domains or tenants to the region they must be sent
+// from (data residency) and routes each message accordingly. Recipients of a
+// message that require different regions are sent as separate messages
+type RegionRouter struct {
+	// Config is used to send (with a per-call region override)
+	Config *Config
+
+	// OnRoute receives every routing decision (audit trail, optional)
+	OnRoute func(decision RouteDecision)
+
+	// TenantTag is the message tag that holds the tenant (default "tenant")
+	TenantTag string
+
+	domains map[string]string
+	mu      sync.RWMutex
+	tenants map[string]string
+}
+
+// NewRegionRouter will return a new router for the Config (the Config region is the default)
+func NewRegionRouter(cfg *Config) *RegionRouter {
+	return &RegionRouter{Config: cfg, domains: make(map[string]string), tenants: make(map[string]string)}
+}
+
+// AddDomain will route recipients of the domain (and its subdomains) to the region
+func (r *RegionRouter) AddDomain(domain, region string) {
+	r.mu.Lock()
+	r.domains[strings.ToLower(domain)] = region
+	r.mu.Unlock()
+}
+
+// AddTenant will route all messages of the tenant to the region (takes precedence over domains)
+func (r *RegionRouter) AddTenant(tenant, region string) {
+	r.mu.Lock()
+	r.tenants[tenant] = region
+	r.mu.Unlock()
+}
+
+// region returns the region and reason for the recipient (or tenant)
+func (r *RegionRouter) region(tenant, recipient string) (string, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if region, ok := r.tenants[tenant]; ok && len(tenant) > 0 {
+		return region, "tenant:" + tenant
+	}
+	if address, err := mail.ParseAddress(recipient); err == nil {
+		recipient = address.Address
+	}
+	for _, domain := range domainCandidates(recipient) {
+		if region, ok := r.domains[domain]; ok {
+			return region, "domain:" + domain
+		}
+	}
+	return r.Config.Region, "default"
+}
+
+// tenant returns the tenant of the message tags
+func (r *RegionRouter) tenant(tags map[string]string) string {
+	tag := r.TenantTag
+	if len(tag) == 0 {
+		tag = defaultTenantTag
+	}
+	return tags[tag]
+}
+
+// Send will split the message by the required region of each recipient and send each part.
+// On failure the results of the regions already sent are returned with a *RegionSendError
+func (r *RegionRouter) Send(ctx context.Context, m *Message, opts ...SendOption) ([]*SendResult, error) {
+	tenant := r.tenant(m.Tags)
+
+	// Group the recipients by region (keeping the order of first appearance)
+	type route struct {
+		msg    *Message
+		reason string
+	}
+	var regions []string
+	routes := make(map[string]*route)
+	add := func(recipient string, field func(*Message) *[]string) {
+		region, reason := r.region(tenant, recipient)
+		rt, ok := routes[region]
+		if !ok {
+			msg := *m
+			msg.To, msg.Cc, msg.Bcc = nil, nil, nil
+			rt = &route{msg: &msg, reason: reason}
+			routes[region] = rt
+			regions = append(regions, region)
+		} else if rt.reason != reason {
+			rt.reason = "mixed"
+		}
+		list := field(rt.msg)
+		*list = append(*list, recipient)
+	}
+	for _, recipient := range m.To {
+		add(recipient, func(msg *Message) *[]string { return &msg.To })
+	}
+	for _, recipient := range m.Cc {
+		add(recipient, func(msg *Message) *[]string { return &msg.Cc })
+	}
+	for _, recipient := range m.Bcc {
+		add(recipient, func(msg *Message) *[]string { return &msg.Bcc })
+	}
+	if len(regions) == 0 {
+		return nil, ErrMissingRecipients
+	}
+
+	results := make([]*SendResult, 0, len(regions))
+	for i, region := range regions {
+		rt := routes[region]
+		r.decide(region, rt.reason, recipients(rt.msg.To, rt.msg.Cc, rt.msg.Bcc))
+		result, err := r.Config.Send(ctx, rt.msg, r.options(opts, region)...)
+		if err != nil {
+			return results, &RegionSendError{Region: region, Unsent: regions[i+1:], Err: err}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SendRaw will send the raw message from the region required by all of its recipients
+func (r *RegionRouter) SendRaw(ctx context.Context, m *RawMessage, opts ...SendOption) (*SendResult, error) {
+	tenant := r.tenant(m.Tags)
+	_, to := rawAddresses(m.Data)
+	if len(to) == 0 {
+		return nil, ErrMissingRecipients
+	}
+
+	region, reason := r.region(tenant, to[0])
+	for _, recipient := range to[1:] {
+		if other, _ := r.region(tenant, recipient); other != region {
+			return nil, fmt.Errorf("%w: %s and %s", ErrRegionConflict, region, other)
+		}
+	}
+	r.decide(region, reason, to)
+	return r.Config.SendRaw(ctx, m, r.options(opts, region)...)
+}
+
+// options returns the options with the region override (none for the Config region, to keep its endpoint)
+func (r *RegionRouter) options(opts []SendOption, region string) []SendOption {
+	options := append([]SendOption{}, opts...)
+	if region != r.Config.Region {
+		options = append(options, WithRegion(region))
+	}
+	return options
+}
+
+// decide will record the routing decision
+func (r *RegionRouter) decide(region, reason string, to []string) {
+	if r.OnRoute != nil {
+		r.OnRoute(RouteDecision{
+			Time:            time.Now().UTC(),
+			Region:          region,
+			Reason:          reason,
+			RecipientHashes: redactRecipients(r.Config.redactor(), to),
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// regionRecorder is a test server that records the signing region and recipients of each request
+type regionRecorder struct {
+	mu       sync.Mutex
+	requests map[string][]string
+}
+
+// handler returns the http handler
+func (rr *regionRecorder) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		region := strings.Split(r.Header.Get("Authorization"), "/")[2]
+		_ = r.ParseForm()
+		rr.mu.Lock()
+		defer rr.mu.Unlock()
+		for key, values := range r.PostForm {
+			if strings.HasPrefix(key, "Destination.") {
+				rr.requests[region] = append(rr.requests[region], values...)
+			}
+		}
+	})
+}
+
+// TestRegionRouter_Send will test the method Send()
+func TestRegionRouter_Send(t *testing.T) {
+	recorder := &regionRecorder{requests: make(map[string][]string)}
+	server := httptest.NewServer(recorder.handler())
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	router := NewRegionRouter(cfg)
+	router.AddDomain("example.de", "eu-central-1")
+	router.AddDomain("example.fr", "eu-west-3")
+	var decisions []RouteDecision
+	router.OnRoute = func(decision RouteDecision) {
+		decisions = append(decisions, decision)
+	}
+
+	results, err := router.Send(context.Background(), &Message{
+		From: "from@example.com", To: []string{"a@example.de", "b@example.com"}, Bcc: []string{"c@mail.example.fr"},
+		Subject: "subject", TextBody: textBody,
+	}, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Region != "eu-central-1" || results[1].Region != "us-east-1" {
+		t.Fatalf("Wrong results: %+v", results)
+	}
+	if got := recorder.requests["eu-central-1"]; len(got) != 1 || got[0] != "a@example.de" {
+		t.Errorf("Wrong eu-central-1 recipients: %v", got)
+	}
+	if got := recorder.requests["eu-west-3"]; len(got) != 1 || got[0] != "c@mail.example.fr" {
+		t.Errorf("Wrong eu-west-3 recipients: %v", got)
+	}
+	if len(decisions) != 3 || decisions[0].Reason != "domain:example.de" || decisions[1].Reason != "default" {
+		t.Errorf("Wrong decisions: %+v", decisions)
+	}
+}
+
+// TestRegionRouter_SendPartialFailure will test the results of the sent regions are returned with the error
+func TestRegionRouter_SendPartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Split(r.Header.Get("Authorization"), "/")[2] == "eu-central-1" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	router := NewRegionRouter(cfg)
+	router.AddDomain("example.de", "eu-central-1")
+	router.AddDomain("example.fr", "eu-west-3")
+
+	results, err := router.Send(context.Background(), &Message{
+		From: "from@example.com", To: []string{"b@example.com", "a@example.de", "c@example.fr"},
+		Subject: "subject", TextBody: textBody,
+	}, WithEndpoint(server.URL))
+	var sendErr *RegionSendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("expected a RegionSendError, got %v", err)
+	}
+	if sendErr.Region != "eu-central-1" || len(sendErr.Unsent) != 1 || sendErr.Unsent[0] != "eu-west-3" {
+		t.Errorf("Wrong error: %+v", sendErr)
+	}
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the response error to be wrapped, got %v", err)
+	}
+	if len(results) != 1 || results[0].Region != "us-east-1" {
+		t.Errorf("Wrong results: %+v", results)
+	}
+}
+
+// TestRegionRouter_Tenant will test the tenant routing
+func TestRegionRouter_Tenant(t *testing.T) {
+	var region string
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		region = strings.Split(r.Header.Get("Authorization"), "/")[2]
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	router := NewRegionRouter(cfg)
+	router.AddDomain("example.com", "us-west-2")
+	router.AddTenant("acme-eu", "eu-west-1")
+
+	results, err := router.Send(context.Background(), &Message{
+		From: "from@example.com", To: []string{"a@example.com", "b@example.com"}, Subject: "subject",
+		Tags: map[string]string{"tenant": "acme-eu"},
+	}, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || region != "eu-west-1" || values.Get("Destination.ToAddresses.member.2") != "b@example.com" {
+		t.Errorf("Wrong routing: %s %v", region, values)
+	}
+}
+
+// TestRegionRouter_SendRaw will test the method SendRaw()
+func TestRegionRouter_SendRaw(t *testing.T) {
+	cfg := &Config{Region: "us-east-1"}
+	router := NewRegionRouter(cfg)
+	router.AddDomain("example.de", "eu-central-1")
+
+	_, err := router.SendRaw(context.Background(), &RawMessage{
+		Data: []byte("From: from@example.com\nTo: a@example.de, b@example.com\n\nbody"),
+	})
+	if !errors.Is(err, ErrRegionConflict) {
+		t.Errorf("expected %v, got %v", ErrRegionConflict, err)
+	}
+	if _, err = router.SendRaw(context.Background(), &RawMessage{Data: []byte("From: from@example.com\n\nbody")}); err != ErrMissingRecipients {
+		t.Errorf("expected %v, got %v", ErrMissingRecipients, err)
+	}
+}
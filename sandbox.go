@@ -0,0 +1,219 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnverifiedRecipient is returned in the SES sandbox when a recipient is not a verified identity
+var ErrUnverifiedRecipient = errors.New("recipient is not a verified identity (ses sandbox)")
+
+// Account is the SES account details of the region (v2 GetAccount)
+type Account struct {
+	DedicatedIPAutoWarmupEnabled bool   `json:"DedicatedIpAutoWarmupEnabled"`
+	EnforcementStatus            string `json:"EnforcementStatus"`
+	ProductionAccessEnabled      bool   `json:"ProductionAccessEnabled"`
+	SendingEnabled               bool   `json:"SendingEnabled"`
+	SendQuota                    struct {
+		Max24HourSend   float64 `json:"Max24HourSend"`
+		MaxSendRate     float64 `json:"MaxSendRate"`
+		SentLast24Hours float64 `json:"SentLast24Hours"`
+	} `json:"SendQuota"`
+}
+
+// GetAccount returns the SES account details of the region (v2 API)
+func (c *Config) GetAccount(ctx context.Context) (*Account, error) {
+	account := &Account{}
+	if err := c.callJSON(ctx, http.MethodGet, "/v2/email/account", account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// InSandbox returns true if the account is in the SES sandbox in the region (v2 API)
+func (c *Config) InSandbox(ctx context.Context) (bool, error) {
+	account, err := c.GetAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !account.ProductionAccessEnabled, nil
+}
+
+// simulatorDomain is the domain of the SES mailbox simulator (allowed in the sandbox)
+const simulatorDomain = "simulator.amazonses.com"
+
+// sandboxRetryAfter is how long a failed sandbox detection is returned before calling GetAccount again
+const sandboxRetryAfter = time.Minute
+
+// SandboxGuard checks the recipients before sending when the account is in the SES sandbox
+// (detected once per region and endpoint with GetAccount, a failure is retried after a minute),
+// instead of getting MessageRejected errors in staging. Unverified recipients block the send, or are replaced by RewriteTo if set.
+// The mailbox simulator addresses (success@simulator.amazonses.com...) are always allowed
+type SandboxGuard struct {
+	// Identities are the verified identities (required)
+	Identities *VerifiedIdentityCache
+
+	// RewriteTo replaces the unverified recipients (ie: a verified staging inbox) instead of blocking
+	RewriteTo string
+
+	mu      sync.Mutex
+	sandbox map[sandboxKey]*sandboxDetection
+}
+
+// sandboxKey is the region and endpoint of a sandbox status
+type sandboxKey struct {
+	region, endpoint string
+}
+
+// sandboxDetection is the sandbox status of a region and endpoint, done is closed once
+// detected (the fields are guarded by SandboxGuard.mu)
+type sandboxDetection struct {
+	done    chan struct{}
+	sandbox bool
+	err     error
+	failed  time.Time
+}
+
+// NewSandboxGuard will return a guard using the verified identities
+func NewSandboxGuard(identities *VerifiedIdentityCache) *SandboxGuard {
+	return &SandboxGuard{Identities: identities}
+}
+
+// inSandbox returns the (cached) sandbox status of the Config account in its region. A single
+// call detects it (without holding the lock), the concurrent calls for the same key wait for it
+func (g *SandboxGuard) inSandbox(ctx context.Context, c *Config) (bool, error) {
+	key := sandboxKey{region: c.Region, endpoint: c.Endpoint}
+	for {
+		g.mu.Lock()
+		detection, ok := g.sandbox[key]
+		if ok && detection.err != nil && time.Since(detection.failed) >= sandboxRetryAfter {
+			ok = false
+		}
+		if !ok {
+			detection = &sandboxDetection{done: make(chan struct{})}
+			if g.sandbox == nil {
+				g.sandbox = make(map[sandboxKey]*sandboxDetection)
+			}
+			g.sandbox[key] = detection
+			g.mu.Unlock()
+			return g.detect(ctx, c, key, detection)
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-detection.done:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		g.mu.Lock()
+		sandbox, err := detection.sandbox, detection.err
+		g.mu.Unlock()
+		if !isContextError(err) { // Otherwise the detecting call was canceled, try again
+			return sandbox, err
+		}
+	}
+}
+
+// detect calls GetAccount and records the result (the failures are kept for sandboxRetryAfter,
+// except when the context is canceled)
+func (g *SandboxGuard) detect(ctx context.Context, c *Config, key sandboxKey, detection *sandboxDetection) (bool, error) {
+	sandbox, err := c.InSandbox(ctx)
+	g.mu.Lock()
+	detection.sandbox, detection.err, detection.failed = sandbox, err, time.Now()
+	if isContextError(err) && g.sandbox[key] == detection {
+		delete(g.sandbox, key)
+	}
+	g.mu.Unlock()
+	close(detection.done)
+	return sandbox, err
+}
+
+// isContextError returns true if the error is a canceled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// recipients returns the recipients with the unverified ones replaced (or an error)
+func (g *SandboxGuard) recipients(ctx context.Context, c *Config, list []string) ([]string, bool, error) {
+	checked := make([]string, 0, len(list))
+	changed, rewritten := false, false
+	for _, recipient := range list {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, false, err
+		}
+		verified := strings.EqualFold(address.Address[strings.LastIndex(address.Address, "@")+1:], simulatorDomain)
+		if !verified {
+			if verified, err = g.Identities.Verified(ctx, c, address.Address); err != nil {
+				return nil, false, err
+			}
+		}
+		switch {
+		case verified:
+			checked = append(checked, recipient)
+		case len(g.RewriteTo) == 0:
+			return nil, false, fmt.Errorf("%w: %s", ErrUnverifiedRecipient, address.Address)
+		case !rewritten:
+			checked, changed, rewritten = append(checked, g.RewriteTo), true, true
+		default:
+			changed = true
+		}
+	}
+	return checked, changed, nil
+}
+
+// Apply returns the message with its recipients checked (a copy if they were rewritten)
+func (g *SandboxGuard) Apply(ctx context.Context, c *Config, m *Message) (*Message, error) {
+	if sandbox, err := g.inSandbox(ctx, c); err != nil || !sandbox {
+		return m, err
+	}
+	msg := *m
+	for _, list := range []*[]string{&msg.To, &msg.Cc, &msg.Bcc} {
+		checked, _, err := g.recipients(ctx, c, *list)
+		if err != nil {
+			return nil, err
+		}
+		*list = checked
+	}
+	return &msg, nil
+}
+
+// ApplyRaw returns the raw message with its To, Cc and Bcc headers checked (a copy if they were rewritten)
+func (g *SandboxGuard) ApplyRaw(ctx context.Context, c *Config, m *RawMessage) (*RawMessage, error) {
+	if sandbox, err := g.inSandbox(ctx, c); err != nil || !sandbox {
+		return m, err
+	}
+	msg := *m
+	parts := splitRaw(msg.Data)
+	for _, key := range []string{"To", "Cc", "Bcc"} {
+		value := parts.get(key)
+		if len(value) == 0 {
+			continue
+		}
+		list, err := mail.ParseAddressList(value)
+		if err != nil {
+			return nil, err
+		}
+		addresses := make([]string, 0, len(list))
+		for _, address := range list {
+			addresses = append(addresses, address.String())
+		}
+		checked, changed, err := g.recipients(ctx, c, addresses)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			if err = parts.set(key, strings.Join(checked, ", ")); err != nil {
+				return nil, err
+			}
+		}
+	}
+	msg.Data = parts.bytes()
+	return &msg, nil
+}
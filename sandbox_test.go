@@ -0,0 +1,220 @@
+package ses
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSandboxConfig returns a Config with a SandboxGuard for a server answering GetAccount,
+// the identity calls (example.com and jane@gmail.com are verified) and the sends
+func newSandboxConfig(production bool, rewriteTo string) (*Config, map[string]int, *url.Values, func()) {
+	calls := make(map[string]int)
+	values := &url.Values{}
+	identities := identityHandler(calls)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/email/account" {
+			calls["GetAccount"]++
+			if production {
+				_, _ = w.Write([]byte(`{"ProductionAccessEnabled": true, "SendingEnabled": true}`))
+			} else {
+				_, _ = w.Write([]byte(`{"ProductionAccessEnabled": false, "SendingEnabled": true, "SendQuota": {"Max24HourSend": 200}}`))
+			}
+			return
+		}
+		_ = r.ParseForm()
+		if action := r.PostForm.Get("Action"); action == "SendEmail" || action == "SendRawEmail" {
+			calls[action]++
+			*values = r.PostForm
+			return
+		}
+		identities(w, r)
+	}))
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	cfg.Sandbox = NewSandboxGuard(NewVerifiedIdentityCache(0))
+	cfg.Sandbox.RewriteTo = rewriteTo
+	return cfg, calls, values, server.Close
+}
+
+// TestConfig_GetAccount will test the sandbox detection
+func TestConfig_GetAccount(t *testing.T) {
+	cfg, _, _, done := newSandboxConfig(false, "")
+	defer done()
+	account, err := cfg.GetAccount(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.ProductionAccessEnabled || !account.SendingEnabled || account.SendQuota.Max24HourSend != 200 {
+		t.Errorf("Wrong account: %+v", account)
+	}
+	if sandbox, _ := cfg.InSandbox(context.Background()); !sandbox {
+		t.Errorf("Expected the sandbox")
+	}
+}
+
+// TestConfig_SendSandboxBlock will test blocking unverified recipients in the sandbox
+func TestConfig_SendSandboxBlock(t *testing.T) {
+	cfg, calls, _, done := newSandboxConfig(false, "")
+	defer done()
+
+	msg := &Message{From: "a@example.com", To: []string{"b@example.com", "john@gmail.com"}, Subject: "s"}
+	if _, err := cfg.Send(context.Background(), msg); !errors.Is(err, ErrUnverifiedRecipient) {
+		t.Errorf("expected ErrUnverifiedRecipient got %v", err)
+	}
+	raw := &RawMessage{Data: []byte("From: a@example.com\r\nTo: b@example.com\r\nCc: John <john@gmail.com>\r\n\r\nbody")}
+	if _, err := cfg.SendRaw(context.Background(), raw); !errors.Is(err, ErrUnverifiedRecipient) {
+		t.Errorf("expected ErrUnverifiedRecipient got %v", err)
+	}
+	msg.To = []string{"b@mail.example.com", "Jane <jane@gmail.com>"}
+	if _, err := cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if calls["SendEmail"] != 1 || calls["SendRawEmail"] != 0 || calls["GetAccount"] != 1 {
+		t.Errorf("Wrong calls: %v", calls)
+	}
+}
+
+// TestConfig_SendSandboxRewrite will test rewriting unverified recipients in the sandbox
+func TestConfig_SendSandboxRewrite(t *testing.T) {
+	cfg, _, values, done := newSandboxConfig(false, "staging@example.com")
+	defer done()
+
+	msg := &Message{From: "a@example.com", To: []string{"john@gmail.com", "b@example.com", "jim@gmail.com"}, Subject: "s"}
+	if _, err := cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("Destination.ToAddresses.member.1") != "staging@example.com" ||
+		values.Get("Destination.ToAddresses.member.2") != "b@example.com" || len(values.Get("Destination.ToAddresses.member.3")) > 0 {
+		t.Errorf("Wrong recipients: %v", values)
+	}
+	if msg.To[0] != "john@gmail.com" {
+		t.Errorf("Expected the message to be unchanged")
+	}
+
+	raw := &RawMessage{Data: []byte("From: a@example.com\r\nTo: b@example.com, John <john@gmail.com>\r\n\r\nbody")}
+	if _, err := cfg.SendRaw(context.Background(), raw); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := base64.StdEncoding.DecodeString(values.Get("RawMessage.Data"))
+	if to := splitRaw(data).get("To"); to != "<b@example.com>, staging@example.com" {
+		t.Errorf("Wrong To header: %s", to)
+	}
+}
+
+// TestConfig_SendSandboxProduction will test that nothing is checked out of the sandbox
+func TestConfig_SendSandboxProduction(t *testing.T) {
+	cfg, calls, _, done := newSandboxConfig(true, "")
+	defer done()
+
+	msg := &Message{From: "a@example.com", To: []string{"john@gmail.com"}, Subject: "s"}
+	for i := 0; i < 2; i++ {
+		if _, err := cfg.Send(context.Background(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls["GetAccount"] != 1 || calls["ListIdentities"] != 0 {
+		t.Errorf("Wrong calls: %v", calls)
+	}
+
+	// Detected again for another region
+	if _, err := cfg.Send(context.Background(), msg, WithRegion("eu-west-1"), WithEndpoint(cfg.Endpoint)); err != nil {
+		t.Fatal(err)
+	}
+	if calls["GetAccount"] != 2 {
+		t.Errorf("expected a detection per region: %v", calls)
+	}
+}
+
+// TestConfig_SendSandboxSimulator will test the mailbox simulator addresses are allowed in the sandbox
+func TestConfig_SendSandboxSimulator(t *testing.T) {
+	cfg, calls, _, done := newSandboxConfig(false, "")
+	defer done()
+
+	msg := &Message{From: "a@example.com", To: []string{"success@simulator.amazonses.com", "Bounce <bounce@SIMULATOR.amazonses.com>"}, Subject: "s"}
+	if _, err := cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if calls["SendEmail"] != 1 {
+		t.Errorf("Wrong calls: %v", calls)
+	}
+}
+
+// TestSandboxGuard_Detection will test a slow detection only blocks its region and failures are kept
+func TestSandboxGuard_Detection(t *testing.T) {
+	var slowCalls, failedCalls int32
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&slowCalls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		_, _ = w.Write([]byte(`{"ProductionAccessEnabled": false}`))
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ProductionAccessEnabled": true}`))
+	}))
+	defer fast.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&failedCalls, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer failing.Close()
+
+	config := func(region, endpoint string) *Config {
+		return &Config{Endpoint: endpoint, Region: region, AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	}
+	guard := NewSandboxGuard(NewVerifiedIdentityCache(0))
+
+	// Concurrent calls wait for a single detection
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sandbox, err := guard.inSandbox(context.Background(), config("us-east-1", slow.URL)); err != nil || !sandbox {
+				t.Errorf("Expected the sandbox: %v", err)
+			}
+		}()
+	}
+	<-started
+
+	// Other regions are not blocked
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if sandbox, err := guard.inSandbox(ctx, config("eu-west-1", fast.URL)); err != nil || sandbox {
+		t.Errorf("Expected production while the other region is detected: %v", err)
+	}
+	close(release)
+	wg.Wait()
+	if calls := atomic.LoadInt32(&slowCalls); calls != 1 {
+		t.Errorf("expected a single detection, got %d", calls)
+	}
+
+	// Failures are kept until sandboxRetryAfter
+	failingConfig := config("us-west-2", failing.URL)
+	for i := 0; i < 2; i++ {
+		var responseErr *ResponseError
+		if _, err := guard.inSandbox(context.Background(), failingConfig); !errors.As(err, &responseErr) {
+			t.Errorf("expected a ResponseError got %v", err)
+		}
+	}
+	if calls := atomic.LoadInt32(&failedCalls); calls != 1 {
+		t.Errorf("expected the failure to be kept, got %d calls", calls)
+	}
+	guard.mu.Lock()
+	guard.sandbox[sandboxKey{region: "us-west-2", endpoint: failing.URL}].failed = time.Now().Add(-sandboxRetryAfter)
+	guard.mu.Unlock()
+	_, _ = guard.inSandbox(context.Background(), failingConfig)
+	if calls := atomic.LoadInt32(&failedCalls); calls != 2 {
+		t.Errorf("expected a retry after %s, got %d calls", sandboxRetryAfter, calls)
+	}
+}
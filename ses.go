@@ -1,14 +1,17 @@
 package ses
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -34,115 +37,498 @@ type Config struct {
 	// SecretAccessKey is your Amazon AWS secret key.
 	SecretAccessKey string
 
+	// SessionToken is the session token for temporary credentials (optional)
+	SessionToken string
+
 	// HTTPClient is a http client to use
 	HTTPClient httpInterface
+
+	// APIVersion selects the SES protocol: APIVersionV1 (default) or APIVersionV2
+	APIVersion string
+
+	// Marshaler overrides the request serialization (takes precedence over APIVersion)
+	Marshaler Marshaler
+
+	// ConfigurationSet is the default configuration set for messages that do not set one
+	ConfigurationSet string
+
+	// SourceArn is the default sending authorization ARN for messages that do not set one
+	SourceArn string
+
+	// AuditSink receives a record of every send attempt (optional)
+	AuditSink AuditSink
+
+	// Redactor obfuscates the recipients in the audit records and routing decisions (default SHA256Redactor)
+	Redactor Redactor
+
+	// PGP encrypts raw messages before sending (optional, formatted messages are rejected)
+	PGP *PGPPolicy
+
+	// FromPolicy validates and rewrites the From address before sending (optional)
+	FromPolicy *FromPolicy
+
+	// Alignment checks the DMARC alignment of the From domain before sending (optional)
+	Alignment *AlignmentPolicy
+
+	// VerifiedIdentities fails sends from unverified identities before calling SES (optional)
+	VerifiedIdentities *VerifiedIdentityCache
+
+	// Sandbox checks (or rewrites) the recipients when the account is in the SES sandbox (optional)
+	Sandbox *SandboxGuard
+
+	// BodyTransformers rewrite the HTML body of formatted messages before sending, in order (optional)
+	BodyTransformers []BodyTransformer
+
+	// AttachmentScanner scans the attachments of raw messages before sending (optional)
+	AttachmentScanner AttachmentScanner
+
+	// HTMLSizeBudget warns about (or truncates) HTML bodies over the size budget (optional)
+	HTMLSizeBudget *HTMLSizeBudget
+
+	// SigningHost is the host the requests are signed for (and sent with), when the Endpoint
+	// is an egress proxy in front of SES (the dialed address and TLS server name stay the Endpoint).
+	// It is ignored when the call is sent to another region or endpoint (WithRegion, WithEndpoint)
+	SigningHost string
+
+	// FollowRedirects re-signs and follows 301, 302, 307 and 308 responses to https locations
+	// on the same host or a regional SES endpoint (otherwise an EndpointMovedError is returned)
+	FollowRedirects bool
+
+	// SigningAlgorithm is SigningAlgorithmV4 (default) or SigningAlgorithmV4A (multi-region)
+	SigningAlgorithm string
+
+	// SigningRegionSet are the regions a SigV4A signature is valid for (default "*")
+	SigningRegionSet []string
+
+	// MessageIDGenerator generates the Message-ID of raw messages that have none and a From
+	// address (default DefaultMessageIDGenerator)
+	MessageIDGenerator MessageIDGenerator
+
+	// rotated holds the credentials.Value set by SetCredentials() (kept out of the exported
+	// fields so a Config can still be copied by value)
+	rotated atomic.Value
 }
 
 // EnvConfig takes the access key ID and secret access key values from the environment variables
-// $AWS_ACCESS_KEY_ID and $AWS_SECRET_KEY, respectively.
+// $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY (or the legacy $AWS_SECRET_KEY), respectively.
 var EnvConfig = Config{
-	AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"), // Set from ENV using standard name
-	Endpoint:        os.Getenv("AWS_SES_ENDPOINT"),  // Set from ENV using standard name
-	HTTPClient:      http.DefaultClient,             // Use a default client unless overridden
-	Region:          os.Getenv("AWS_REGION"),        // Set from ENV using standard name
-	SecretAccessKey: os.Getenv("AWS_SECRET_KEY"),    // Set from ENV using standard name
+	AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),                    // Set from ENV using standard name
+	Endpoint:        os.Getenv("AWS_SES_ENDPOINT"),                     // Set from ENV using standard name
+	HTTPClient:      http.DefaultClient,                                // Use a default client unless overridden
+	Region:          os.Getenv("AWS_REGION"),                           // Set from ENV using standard name
+	SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY"), // Set from ENV using standard name (or legacy)
+	SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),                    // Set from ENV using standard name
 }
 
-// fillRecipients will fill all recipients into the data.values
-func (c *Config) fillRecipients(from string, to, cc, bcc []string, data url.Values) {
-	data.Add("Source", from)
-
-	// todo: remove IF cases, since if empty, for loop will skip anyway?
-	if len(to) > 0 {
-		for i := 0; i < len(to); i++ {
-			data.Add(fmt.Sprintf("Destination.ToAddresses.member.%d", i+1), to[i])
+// getEnv returns the value of the first environment variable that is set
+func getEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); len(value) > 0 {
+			return value
 		}
 	}
-	if len(cc) > 0 {
-		for i := 0; i < len(cc); i++ {
-			data.Add(fmt.Sprintf("Destination.CcAddresses.member.%d", i+1), cc[i])
-		}
+	return ""
+}
+
+// SetCredentials will rotate the credentials at runtime. It is safe to call while
+// sending, any request signed afterwards (including retries) uses the new credentials.
+// The exported credential fields are left unchanged (they are not safe to write while sending)
+func (c *Config) SetCredentials(accessKeyID, secretAccessKey, sessionToken string) {
+	c.rotated.Store(credentials.Value{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken})
+}
+
+// credentials returns a consistent snapshot of the credentials (rotated or configured)
+func (c *Config) credentials() credentials.Value {
+	if rotated, ok := c.rotated.Load().(credentials.Value); ok {
+		return rotated
 	}
-	if len(bcc) > 0 {
-		for i := 0; i < len(bcc); i++ {
-			data.Add(fmt.Sprintf("Destination.BccAddresses.member.%d", i+1), bcc[i])
-		}
+	return credentials.Value{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}
+}
+
+// marshaler returns the Marshaler for the configured API version
+func (c *Config) marshaler() Marshaler {
+	if c.Marshaler != nil {
+		return c.Marshaler
+	} else if c.APIVersion == APIVersionV2 {
+		return &JSONMarshaler{}
 	}
+	return &QueryMarshaler{AccessKeyID: c.credentials().AccessKeyID}
 }
 
 // SendEmail sends a plain text email. Note that from must be a verified
 // address in the AWS control panel.
 func (c *Config) SendEmail(from string, to, cc, bcc []string, subject, body string) (string, error) {
-	data := make(url.Values)
-	data.Add("Action", "SendEmail")
-	c.fillRecipients(from, to, cc, bcc, data)
-	data.Add("Message.Subject.Data", subject)
-	data.Add("Message.Body.Text.Data", body)
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
-	return c.sesPost(data)
+	return c.SendMessage(&Message{From: from, To: to, Cc: cc, Bcc: bcc, Subject: subject, TextBody: body})
 }
 
 // SendEmailHTML sends an HTML email. Note that from must be a verified address
 // in the AWS control panel.
 func (c *Config) SendEmailHTML(from string, to, cc, bcc []string, subject, bodyText, bodyHTML string) (string, error) {
-	data := make(url.Values)
-	data.Add("Action", "SendEmail")
-	c.fillRecipients(from, to, cc, bcc, data)
-	data.Add("Message.Subject.Data", subject)
-	data.Add("Message.Body.Text.Data", bodyText)
-	data.Add("Message.Body.Html.Data", bodyHTML)
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
-	return c.sesPost(data)
+	return c.SendMessage(&Message{
+		From: from, To: to, Cc: cc, Bcc: bcc, Subject: subject, TextBody: bodyText, HTMLBody: bodyHTML,
+	})
 }
 
 // SendRawEmail sends a raw email. Note that from must be a verified address
 // in the AWS control panel.
 func (c *Config) SendRawEmail(raw []byte) (string, error) {
-	data := make(url.Values)
-	data.Add("Action", "SendRawEmail")
-	data.Add("RawMessage.Data", base64.StdEncoding.EncodeToString(raw))
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
-	return c.sesPost(data)
+	return c.SendRawMessage(&RawMessage{Data: raw})
+}
+
+// SendResult is the result of a successful send
+type SendResult struct {
+	// Body is the raw response body from SES
+	Body string
+
+	// MessageID is the SES message id parsed from the response
+	MessageID string
+
+	// Region is the region the message was sent from
+	Region string
+
+	// HTMLSize is the size in bytes of the HTML body that was sent (formatted messages)
+	HTMLSize int
+
+	// Truncated is true if the HTML body was truncated to fit the HTMLSizeBudget
+	Truncated bool
+
+	// Stats are the latency measurements (only set when using WithStats(), also on errors)
+	Stats *Stats
+}
+
+// ResponseError is returned when SES responds with an error status code
+type ResponseError struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Error returns the status code and the response body
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("error code %d. response: %s", e.StatusCode, e.Body)
+}
+
+// SendMessage sends a formatted email using the configured Marshaler
+func (c *Config) SendMessage(m *Message) (string, error) {
+	result, err := c.Send(context.Background(), m)
+	if err != nil {
+		return "", err
+	}
+	return result.Body, nil
+}
+
+// SendRawMessage sends a raw email using the configured Marshaler
+func (c *Config) SendRawMessage(m *RawMessage) (string, error) {
+	result, err := c.SendRaw(context.Background(), m)
+	if err != nil {
+		return "", err
+	}
+	return result.Body, nil
+}
+
+// Send sends a formatted email with per-call options (see SendOption)
+func (c *Config) Send(ctx context.Context, m *Message, opts ...SendOption) (*SendResult, error) {
+	o := newSendOptions(opts)
+	msg := *m
+	var result *SendResult
+	err := m.Validate()
+	if err == nil {
+		result, err = c.send(ctx, &msg, o)
+	}
+	c.audit(actionSendEmail, o, msg.From, recipients(msg.To, msg.Cc, msg.Bcc), result, err)
+	return result, err
+}
+
+// send runs the guards and transformers on the message (in place) and sends it
+func (c *Config) send(ctx context.Context, msg *Message, o *sendOptions) (*SendResult, error) {
+	if c.PGP != nil {
+		return nil, ErrPGPFormattedMessage
+	}
+	truncated, err := c.prepare(ctx, msg, o)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.marshaler().MarshalMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyRawParams(req, o.rawParams); err != nil {
+		return nil, err
+	}
+	result, err := c.sesPost(ctx, req, o)
+	if result != nil {
+		result.HTMLSize, result.Truncated = len(msg.HTMLBody), truncated
+	}
+	return result, err
+}
+
+// prepare runs the defaults, guards and transformers on the message (in place), returns
+// true if the HTML body was truncated to fit the HTMLSizeBudget
+func (c *Config) prepare(ctx context.Context, msg *Message, o *sendOptions) (bool, error) {
+	applyContextDefaults(ctx, &msg.ConfigurationSet, &msg.Tags)
+	c.applyDefaults(&msg.ConfigurationSet, &msg.SourceArn)
+	if c.FromPolicy != nil {
+		rewritten, err := c.FromPolicy.Apply(msg)
+		if err != nil {
+			return false, err
+		}
+		*msg = *rewritten
+	}
+	if c.Alignment != nil {
+		if _, err := c.Alignment.Check(msg.From); err != nil {
+			return false, err
+		}
+	}
+	if c.VerifiedIdentities != nil {
+		if err := c.VerifiedIdentities.Check(ctx, c.sender(o), msg.From); err != nil {
+			return false, err
+		}
+	}
+	if c.Sandbox != nil {
+		checked, err := c.Sandbox.Apply(ctx, c.sender(o), msg)
+		if err != nil {
+			return false, err
+		}
+		*msg = *checked
+	}
+	if len(msg.HTMLBody) > 0 && len(c.BodyTransformers) > 0 {
+		var err error
+		if msg.HTMLBody, err = transformHTML(msg.HTMLBody, c.BodyTransformers); err != nil {
+			return false, err
+		}
+	}
+	truncated := false
+	if c.HTMLSizeBudget != nil {
+		msg.HTMLBody, truncated = c.HTMLSizeBudget.Apply(msg.HTMLBody)
+	}
+	return truncated, nil
+}
+
+// SendRaw sends a raw email with per-call options (see SendOption)
+func (c *Config) SendRaw(ctx context.Context, m *RawMessage, opts ...SendOption) (*SendResult, error) {
+	o := newSendOptions(opts)
+	msg := *m
+	var result *SendResult
+	err := m.Validate()
+	if err == nil {
+		result, err = c.sendRaw(ctx, &msg, o)
+	}
+	if c.AuditSink != nil {
+		from, to := rawAddresses(msg.Data)
+		c.audit(actionSendRawEmail, o, from, to, result, err)
+	}
+	return result, err
+}
+
+// sendRaw runs the guards and transformers on the raw message (in place) and sends it
+func (c *Config) sendRaw(ctx context.Context, msg *RawMessage, o *sendOptions) (*SendResult, error) {
+	if headers := applyContextDefaults(ctx, &msg.ConfigurationSet, &msg.Tags); len(headers) > 0 {
+		parts := splitRaw(msg.Data)
+		for _, key := range sortedKeys(headers) {
+			if len(parts.get(key)) > 0 {
+				continue
+			}
+			if err := parts.set(key, headers[key]); err != nil {
+				return nil, err
+			}
+		}
+		msg.Data = parts.bytes()
+	}
+	c.applyDefaults(&msg.ConfigurationSet, &msg.SourceArn)
+	if c.FromPolicy != nil {
+		rewritten, err := c.FromPolicy.ApplyRaw(msg)
+		if err != nil {
+			return nil, err
+		}
+		*msg = *rewritten
+	}
+	if c.Alignment != nil {
+		if _, err := c.Alignment.CheckRaw(msg); err != nil {
+			return nil, err
+		}
+	}
+	if c.VerifiedIdentities != nil {
+		if err := c.VerifiedIdentities.Check(ctx, c.sender(o), splitRaw(msg.Data).get("From")); err != nil {
+			return nil, err
+		}
+	}
+	if c.Sandbox != nil {
+		checked, err := c.Sandbox.ApplyRaw(ctx, c.sender(o), msg)
+		if err != nil {
+			return nil, err
+		}
+		*msg = *checked
+	}
+	if c.AttachmentScanner != nil {
+		if err := scanAttachments(ctx, c.AttachmentScanner, msg); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := msg.EnsureMessageID(c.MessageIDGenerator); err != nil {
+		return nil, err
+	}
+	if c.PGP != nil {
+		encrypted, err := c.PGP.EncryptRawMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		*msg = *encrypted
+	}
+	req, err := c.marshaler().MarshalRawMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if err = applyRawParams(req, o.rawParams); err != nil {
+		return nil, err
+	}
+	return c.sesPost(ctx, req, o)
+}
+
+// applyDefaults will set the Config defaults on the message fields that are empty
+func (c *Config) applyDefaults(configurationSet, sourceArn *string) {
+	if len(*configurationSet) == 0 {
+		*configurationSet = c.ConfigurationSet
+	}
+	if len(*sourceArn) == 0 {
+		*sourceArn = c.SourceArn
+	}
+}
+
+// target returns the region and endpoint for the call (Config or overridden by the options)
+func (c *Config) target(o *sendOptions) (region, endpoint string) {
+	region, endpoint = c.Region, c.Endpoint
+	if len(o.region) > 0 {
+		region, endpoint = o.region, RegionEndpoint(o.region)
+	}
+	if len(o.endpoint) > 0 {
+		endpoint = o.endpoint
+	}
+	return
+}
+
+// sender returns the Config sending the call: a copy for the region and endpoint of the
+// options (if overridden), used by the guards that call SES for the sending region
+func (c *Config) sender(o *sendOptions) *Config {
+	if len(o.region) == 0 && len(o.endpoint) == 0 {
+		return c
+	}
+	region, endpoint := c.target(o)
+	return c.Clone(OverrideRegion(region), OverrideEndpoint(endpoint))
 }
 
 // sigv4 signs using the new V4 signature method
-func (c *Config) sigv4(req *http.Request, body string, timestamp time.Time) error {
-	awsCredentials := credentials.NewCredentials(&credentials.StaticProvider{Value: credentials.Value{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey}})
-	_, err := awssigner.NewSigner(awsCredentials).Sign(req, strings.NewReader(body), "email", c.Region, timestamp)
+func (c *Config) sigv4(req *http.Request, body []byte, service, region string, timestamp time.Time) error {
+	awsCredentials := credentials.NewCredentials(&credentials.StaticProvider{Value: c.credentials()})
+	_, err := awssigner.NewSigner(awsCredentials).Sign(req, bytes.NewReader(body), service, region, timestamp)
 	return err
 }
 
-// sesPost fires the actual HTTP post request with the email data
-func (c *Config) sesPost(data url.Values) (string, error) {
+// sesPost fires the actual HTTP post request with the marshaled request
+func (c *Config) sesPost(ctx context.Context, r *Request, o *sendOptions) (*SendResult, error) {
+	result := &SendResult{}
+
+	// Trace the request timings
+	var tracer *statsTracer
+	if o.stats {
+		result.Stats = &Stats{}
+		tracer = newStatsTracer(result.Stats)
+		ctx = httptrace.WithClientTrace(ctx, tracer.clientTrace())
+	}
+
+	// Keep the stats of a failed request (returned alongside the error)
+	failed := func(err error) (*SendResult, error) {
+		if tracer == nil {
+			return nil, err
+		}
+		tracer.done()
+		return result, err
+	}
 
+	// Fire the request (following the redirects if enabled)
+	region, endpoint := c.target(o)
+	target, signingHost := endpoint+r.Path, c.SigningHost
+	if len(o.region) > 0 || len(o.endpoint) > 0 {
+		signingHost = "" // The proxy is for the Config endpoint
+	}
+	var resp *http.Response
+	var resultBody []byte
+	for redirects := 0; ; redirects++ {
+		var err error
+		if resp, resultBody, err = c.do(ctx, r, target, region, signingHost); err != nil {
+			return failed(err)
+		}
+		if !isRedirect(resp.StatusCode) {
+			break
+		}
+		location, err := resp.Location()
+		if err != nil {
+			return failed(fmt.Errorf("error code %d without a location. response: %s", resp.StatusCode, resultBody))
+		}
+		current, _ := url.Parse(target)
+		if !c.FollowRedirects || redirects >= maxRedirects || current == nil || !followable(current, location) {
+			return failed(&EndpointMovedError{StatusCode: resp.StatusCode, Location: location.String()})
+		}
+		if !sameHost(current, location) {
+			signingHost = "" // Sign for the new host
+		}
+		target = location.String()
+		if movedRegion := regionFromHost(location.Hostname()); len(movedRegion) > 0 {
+			region = movedRegion
+		}
+	}
+	result.Region = region
+
+	// Test the status code
+	if resp.StatusCode != http.StatusOK {
+		return failed(&ResponseError{StatusCode: resp.StatusCode, Body: resultBody})
+	}
+
+	// Record the total time
+	if tracer != nil {
+		tracer.done()
+	}
+
+	// Return the body as a string
+	result.Body = string(resultBody)
+	result.MessageID = parseMessageID(resultBody)
+	return result, nil
+}
+
+// do signs and fires a single request, returning the response and its body
+func (c *Config) do(ctx context.Context, r *Request, target, region, signingHost string) (*http.Response, []byte, error) {
 	// Set the request with context
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.Endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, r.Method, target, nil)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
-	// Set the content type header
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// Sign for the SES host when sending through a proxy
+	if len(signingHost) > 0 {
+		req.Host = signingHost
+	}
+
+	// Set the content type header (if there is a body)
+	if len(r.ContentType) > 0 {
+		req.Header.Set("Content-Type", r.ContentType)
+	}
 
 	// Set the date/time
 	now := time.Now().UTC()
 	req.Header.Set("Date", now.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
 
-	// Sign with AWS SigV4
-	if err = c.sigv4(req, data.Encode(), now); err != nil {
-		return "", err
+	// Sign with AWS SigV4 (or SigV4A)
+	if c.SigningAlgorithm == SigningAlgorithmV4A {
+		err = c.sigv4a(req, r.Body, r.SigningName, now)
+	} else {
+		err = c.sigv4(req, r.Body, r.SigningName, region, now)
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Fire the request
 	var resp *http.Response
-	if resp, err = c.HTTPClient.Do(req); err != nil {
-		return "", err
-	}
-
-	// Read the body
-	var resultBody []byte
-	if resultBody, err = ioutil.ReadAll(resp.Body); err != nil {
-		return "", err
+	if resp, err = noRedirects(c.HTTPClient).Do(req); err != nil {
+		return nil, nil, err
 	}
 
 	// Close the body reader
@@ -150,11 +536,28 @@ func (c *Config) sesPost(data url.Values) (string, error) {
 		_ = resp.Body.Close()
 	}()
 
-	// Test the status code
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error code %d. response: %s", resp.StatusCode, resultBody)
+	// Read the body
+	var body []byte
+	if body, err = ioutil.ReadAll(resp.Body); err != nil {
+		return nil, nil, err
 	}
+	return resp, body, nil
+}
 
-	// Return the body as a string
-	return string(resultBody), nil
+// parseMessageID will find the message id in a v1 (XML) or v2 (JSON) response
+func parseMessageID(body []byte) string {
+	var v2 struct {
+		MessageID string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(body, &v2); err == nil {
+		return v2.MessageID
+	}
+	var v1 struct {
+		Email string `xml:"SendEmailResult>MessageId"`
+		Raw   string `xml:"SendRawEmailResult>MessageId"`
+	}
+	if err := xml.Unmarshal(body, &v1); err == nil {
+		return v1.Email + v1.Raw
+	}
+	return ""
 }
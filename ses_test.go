@@ -2,6 +2,7 @@ package ses
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"flag"
 	"fmt"
@@ -9,9 +10,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	awssigner "github.com/aws/aws-sdk-go/aws/signer/v4"
 )
 
 var to, cc, bcc, from string
@@ -197,6 +202,159 @@ func TestConfig_SendEmailError(t *testing.T) {
 	}
 }
 
+// TestConfig_SendMessageV2 will test the method SendMessage() using the v2 API
+func TestConfig_SendMessageV2(t *testing.T) {
+	var path, auth, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient, APIVersion: APIVersionV2}
+	_, err := cfg.SendMessage(&Message{From: "from", To: []string{to}, Subject: "amazon SES v2 test", TextBody: textBody})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v2/email/outbound-emails" {
+		t.Errorf("Wrong path: %s", path)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Wrong content type: %s", contentType)
+	}
+	expected := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=a/%s/region/ses/aws4_request", time.Now().UTC().Format("20060102"))
+	if !strings.HasPrefix(auth, expected) {
+		t.Errorf("Wrong signature: expected: %s got %s", expected, auth)
+	}
+
+	if _, err = cfg.SendMessage(&Message{From: "from"}); err != ErrMissingRecipients {
+		t.Errorf("expected %v, got %v", ErrMissingRecipients, err)
+	}
+}
+
+// TestConfig_SetCredentials will test the method SetCredentials()
+func TestConfig_SetCredentials(t *testing.T) {
+	var auth, token string
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		token = r.Header.Get("X-Amz-Security-Token")
+		body, _ := ioutil.ReadAll(r.Body)
+		values, _ = url.ParseQuery(string(body))
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	cfg.SetCredentials("rotated", "secret", "token")
+	if _, err := cfg.SendEmail("from", []string{to}, nil, nil, "subject", textBody); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=rotated/") {
+		t.Errorf("Wrong credentials: %s", auth)
+	}
+	if token != "token" {
+		t.Errorf("Wrong session token: %s", token)
+	}
+	if values.Get("AWSAccessKeyId") != "rotated" {
+		t.Errorf("Wrong key")
+	}
+}
+
+// TestConfig_CopyByValue will test a Config can be copied by value (go vet copylocks)
+func TestConfig_CopyByValue(t *testing.T) {
+	cfg := Config{AccessKeyID: "a", SecretAccessKey: "s"}
+	cp := cfg
+	cp.SetCredentials("rotated", "secret", "")
+	if cfg.credentials().AccessKeyID != "a" || cp.credentials().AccessKeyID != "rotated" {
+		t.Errorf("Expected the copies to be independent")
+	}
+	if cp.AccessKeyID != "a" {
+		t.Errorf("Expected the exported fields to be unchanged: %s", cp.AccessKeyID)
+	}
+}
+
+// TestGetEnv will test the method getEnv()
+func TestGetEnv(t *testing.T) {
+	_ = os.Setenv("GO_SES_TEST_LEGACY", "legacy")
+	defer func() {
+		_ = os.Unsetenv("GO_SES_TEST_LEGACY")
+	}()
+	if value := getEnv("GO_SES_TEST_MISSING", "GO_SES_TEST_LEGACY"); value != "legacy" {
+		t.Errorf("expected legacy got %s", value)
+	}
+	if value := getEnv("GO_SES_TEST_MISSING"); len(value) > 0 {
+		t.Errorf("expected empty got %s", value)
+	}
+}
+
+// TestParseMessageID will test the method parseMessageID()
+func TestParseMessageID(t *testing.T) {
+	tests := map[string]string{
+		`<SendRawEmailResponse><SendRawEmailResult><MessageId>raw-1</MessageId></SendRawEmailResult></SendRawEmailResponse>`: "raw-1",
+		`{"MessageId":"v2-1"}`: "v2-1",
+		``:                     "",
+	}
+	for body, expected := range tests {
+		if id := parseMessageID([]byte(body)); id != expected {
+			t.Errorf("expected %q got %q", expected, id)
+		}
+	}
+}
+
+// TestConfig_SendSigningHost will test signing for the SES host while dialing a proxy
+func TestConfig_SendSigningHost(t *testing.T) {
+	const signingHost = "email.us-east-1.amazonaws.com"
+	var host, authorization, expected string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, authorization = r.Host, r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+
+		// Sign the same request for the SES endpoint
+		check, _ := http.NewRequest(r.Method, "https://"+signingHost+r.URL.RequestURI(), nil)
+		check.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+		check.Header.Set("Date", r.Header.Get("Date"))
+		signedAt, _ := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		signer := awssigner.NewSigner(credentials.NewStaticCredentials("a", "s", ""))
+		_, _ = signer.Sign(check, bytes.NewReader(body), "email", "us-east-1", signedAt)
+		expected = check.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		SigningHost: signingHost,
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}, Subject: "s"}); err != nil {
+		t.Fatal(err)
+	}
+	if host != signingHost {
+		t.Errorf("Expected the Host header %s got %s", signingHost, host)
+	}
+	if len(authorization) == 0 || authorization != expected {
+		t.Errorf("Expected a signature for %s:\n%s\ngot\n%s", signingHost, expected, authorization)
+	}
+
+	// Sent to another endpoint: the Host is the endpoint's
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer other.Close()
+	if _, err := cfg.Send(context.Background(), &Message{From: "from@example.com", To: []string{to}, Subject: "s"},
+		WithEndpoint(other.URL)); err != nil {
+		t.Fatal(err)
+	}
+	if host != other.Listener.Addr().String() {
+		t.Errorf("Expected the Host header %s got %s", other.Listener.Addr(), host)
+	}
+	if clone := cfg.Clone(OverrideRegion("eu-west-1")); len(clone.SigningHost) > 0 {
+		t.Errorf("Expected no SigningHost after OverrideRegion: %s", clone.SigningHost)
+	}
+	if clone := cfg.Clone(OverrideEndpoint(other.URL)); len(clone.SigningHost) > 0 {
+		t.Errorf("Expected no SigningHost after OverrideEndpoint: %s", clone.SigningHost)
+	}
+}
+
 //
 // Live Integration Tests
 //
@@ -0,0 +1,203 @@
+package ses
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signing algorithms (see Config.SigningAlgorithm)
+const (
+	SigningAlgorithmV4  = "AWS4-HMAC-SHA256"       // Standard SigV4 (default)
+	SigningAlgorithmV4A = "AWS4-ECDSA-P256-SHA256" // SigV4A (multi-region)
+)
+
+// amzDateFormat is the format of the X-Amz-Date header
+const amzDateFormat = "20060102T150405Z"
+
+// errKeyDerivation is returned if no valid SigV4A key could be derived (practically impossible)
+var errKeyDerivation = errors.New("unable to derive sigv4a signing key")
+
+// ecdsaSignature is the ASN.1 DER encoding of an ECDSA signature (ecdsa.SignASN1 needs go1.15)
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// sigv4a signs the request with SigV4A, using the region set instead of a single region
+func (c *Config) sigv4a(req *http.Request, body []byte, service string, timestamp time.Time) error {
+	creds := c.credentials()
+	key, err := deriveSigV4AKey(creds.AccessKeyID, creds.SecretAccessKey)
+	if err != nil {
+		return err
+	}
+
+	regionSet := "*"
+	if len(c.SigningRegionSet) > 0 {
+		regionSet = strings.Join(c.SigningRegionSet, ",")
+	}
+	amzDate := timestamp.UTC().Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Region-Set", regionSet)
+	if len(creds.SessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonical, signedHeaders := canonicalRequest(req, body)
+	scope := amzDate[:8] + "/" + service + "/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := SigningAlgorithmV4A + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	digest := sha256.Sum256([]byte(stringToSign))
+	r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return err
+	}
+	signature, err := asn1.Marshal(ecdsaSignature{R: r, S: sig})
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", SigningAlgorithmV4A+" Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+hex.EncodeToString(signature))
+	if len(body) > 0 {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+	return nil
+}
+
+// canonicalRequest returns the SigV4 canonical request and the signed headers
+func canonicalRequest(req *http.Request, body []byte) (string, string) {
+	// Sign the host and all content-type, date and x-amz-* headers
+	host := req.Host
+	if len(host) == 0 {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for key, values := range req.Header {
+		key = strings.ToLower(key)
+		if key == "content-type" || key == "date" || strings.HasPrefix(key, "x-amz-") {
+			trimmed := make([]string, 0, len(values))
+			for _, value := range values {
+				trimmed = append(trimmed, strings.Join(strings.Fields(value), " "))
+			}
+			headers[key] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	path := req.URL.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+	payload := sha256.Sum256(body)
+	signedHeaders := strings.Join(names, ";")
+	return strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payload[:]),
+	}, "\n"), signedHeaders
+}
+
+// canonicalQuery returns the sorted and (RFC 3986) encoded query string
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes everything except the unreserved characters (RFC 3986)
+func uriEncode(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+// deriveSigV4AKey derives the P-256 signing key from the access key pair, using the
+// NIST SP 800-108 counter mode KDF (HMAC-SHA256) as specified for SigV4A
+func deriveSigV4AKey(accessKeyID, secretAccessKey string) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	params := curve.Params()
+	nMinusTwo := new(big.Int).Sub(params.N, big.NewInt(2)).Bytes()
+	inputKey := []byte("AWS4A" + secretAccessKey)
+
+	d := new(big.Int)
+	for counter := 1; ; counter++ {
+		if counter > 0xFF {
+			return nil, errKeyDerivation
+		}
+		context := append([]byte(accessKeyID), byte(counter))
+		candidate := hmacKeyDerivation(inputKey, []byte(SigningAlgorithmV4A), context, params.BitSize)
+		if constantTimeCompare(candidate, nMinusTwo) < 0 {
+			d.SetBytes(candidate)
+			break
+		}
+	}
+	d.Add(d, big.NewInt(1))
+
+	key := &ecdsa.PrivateKey{D: d}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+	return key, nil
+}
+
+// hmacKeyDerivation is the NIST SP 800-108 KDF in counter mode using HMAC-SHA256
+func hmacKeyDerivation(key, label, context []byte, bitLen int) []byte {
+	h := hmac.New(sha256.New, key)
+	size := bitLen / 8
+	var out []byte
+	for i := uint32(1); len(out) < size; i++ {
+		h.Reset()
+		_ = binary.Write(h, binary.BigEndian, i)
+		_, _ = h.Write(label)
+		_, _ = h.Write([]byte{0x00})
+		_, _ = h.Write(context)
+		_ = binary.Write(h, binary.BigEndian, uint32(bitLen))
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
+
+// constantTimeCompare compares two big-endian numbers of the same length in
+// constant time, returns -1, 0 or 1
+func constantTimeCompare(a, b []byte) int {
+	if len(b) < len(a) {
+		b = append(make([]byte, len(a)-len(b)), b...)
+	}
+	aLarger, bLarger := 0, 0
+	for i := 0; i < len(a); i++ {
+		x, y := int(a[i]), int(b[i])
+		aLarger |= ((y - x) >> 8) & 1 &^ bLarger
+		bLarger |= ((x - y) >> 8) & 1 &^ aLarger
+	}
+	return aLarger - bLarger
+}
@@ -0,0 +1,100 @@
+package ses
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestDeriveSigV4AKey will test the method deriveSigV4AKey()
+func TestDeriveSigV4AKey(t *testing.T) {
+	key, err := deriveSigV4AKey("AKISORANDOMAASORANDOM", "q+jcrXGc+0zWN6uzclKVhvMmUsIfRPa4rlRandom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedX, _ := new(big.Int).SetString("15D242CEEBF8D8169FD6A8B5A746C41140414C3B07579038DA06AF89190FFFCB", 16)
+	expectedY, _ := new(big.Int).SetString("0515242CEDD82E94799482E4C0514B505AFCCF2C0C98D6A553BF539F424C5EC0", 16)
+	if key.X.Cmp(expectedX) != 0 || key.Y.Cmp(expectedY) != 0 {
+		t.Errorf("Wrong public key: %X %X", key.X, key.Y)
+	}
+}
+
+// TestConstantTimeCompare will test the method constantTimeCompare()
+func TestConstantTimeCompare(t *testing.T) {
+	tests := []struct {
+		a, b     []byte
+		expected int
+	}{
+		{[]byte{1, 2}, []byte{1, 2}, 0},
+		{[]byte{1, 2}, []byte{1, 3}, -1},
+		{[]byte{2, 0}, []byte{1, 255}, 1},
+		{[]byte{0, 5}, []byte{4}, 1},
+	}
+	for _, test := range tests {
+		if result := constantTimeCompare(test.a, test.b); result != test.expected {
+			t.Errorf("%v %v: expected %d got %d", test.a, test.b, test.expected, result)
+		}
+	}
+}
+
+// TestConfig_SendSigV4A will test signing with SigV4A
+func TestConfig_SendSigV4A(t *testing.T) {
+	var req *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "AKISORANDOMAASORANDOM", SecretAccessKey: "q+jcrXGc+0zWN6uzclKVhvMmUsIfRPa4rlRandom",
+		HTTPClient: http.DefaultClient, SigningAlgorithm: SigningAlgorithmV4A, SigningRegionSet: []string{"us-east-1", "eu-west-1"},
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "from", To: []string{to}, Subject: "sigv4a test", TextBody: textBody}); err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("X-Amz-Region-Set") != "us-east-1,eu-west-1" {
+		t.Errorf("Wrong region set: %s", req.Header.Get("X-Amz-Region-Set"))
+	}
+	if !strings.Contains(string(body), "Action=SendEmail") {
+		t.Errorf("Wrong body: %s", body)
+	}
+
+	// Parse the authorization header
+	auth := req.Header.Get("Authorization")
+	date := req.Header.Get("X-Amz-Date")
+	expected := SigningAlgorithmV4A + " Credential=AKISORANDOMAASORANDOM/" + date[:8] + "/email/aws4_request, SignedHeaders="
+	if !strings.HasPrefix(auth, expected) {
+		t.Fatalf("Wrong authorization: %s", auth)
+	}
+	signature, err := hex.DecodeString(auth[strings.Index(auth, "Signature=")+10:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the signature using the public key
+	canonical, signedHeaders := canonicalRequest(req, body)
+	if signedHeaders != "content-type;date;host;x-amz-date;x-amz-region-set" {
+		t.Errorf("Wrong signed headers: %s", signedHeaders)
+	}
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := SigningAlgorithmV4A + "\n" + date + "\n" + date[:8] + "/email/aws4_request\n" + hex.EncodeToString(hash[:])
+	digest := sha256.Sum256([]byte(stringToSign))
+	key, _ := deriveSigV4AKey(cfg.AccessKeyID, cfg.SecretAccessKey)
+	var parsed ecdsaSignature
+	if _, err = asn1.Unmarshal(signature, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.Verify(&key.PublicKey, digest[:], parsed.R, parsed.S) {
+		t.Errorf("invalid signature")
+	}
+}
@@ -0,0 +1,84 @@
+package ses
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Stats are the latency measurements of a single send (collected using httptrace)
+//
+// Phases that did not happen (ie: a reused connection skips DNS, Connect and TLS) are zero
+type Stats struct {
+	DNS     time.Duration // DNS lookup
+	Connect time.Duration // TCP connect (of the first connection that was established)
+	TLS     time.Duration // TLS handshake
+	TTFB    time.Duration // Request start until the first response byte
+	Total   time.Duration // Request start until the response body was read
+
+	// ConnReused is true if an idle connection was reused
+	ConnReused bool
+}
+
+// statsTracer records the timings for a Stats
+//
+// The dialer can race several connections (ie: IPv4 and IPv6), so the hooks are locked
+// and only the first established connection is recorded
+type statsTracer struct {
+	mu            sync.Mutex
+	stats         *Stats
+	start         time.Time
+	dnsStart      time.Time
+	connectStarts map[string]time.Time
+	connected     bool
+	tlsStart      time.Time
+}
+
+// newStatsTracer will start a new tracer
+func newStatsTracer(stats *Stats) *statsTracer {
+	return &statsTracer{stats: stats, start: time.Now(), connectStarts: make(map[string]time.Time)}
+}
+
+// clientTrace returns the httptrace hooks that fill the stats
+func (s *statsTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { s.locked(func() { s.dnsStart = time.Now() }) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			s.locked(func() { s.stats.DNS = time.Since(s.dnsStart) })
+		},
+		ConnectStart: func(network, addr string) {
+			s.locked(func() { s.connectStarts[network+addr] = time.Now() })
+		},
+		ConnectDone: func(network, addr string, err error) {
+			s.locked(func() {
+				if err == nil && !s.connected {
+					s.connected = true
+					s.stats.Connect = time.Since(s.connectStarts[network+addr])
+				}
+			})
+		},
+		TLSHandshakeStart: func() { s.locked(func() { s.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			s.locked(func() { s.stats.TLS = time.Since(s.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			s.locked(func() { s.stats.ConnReused = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			s.locked(func() { s.stats.TTFB = time.Since(s.start) })
+		},
+	}
+}
+
+// locked runs fn while holding the tracer lock
+func (s *statsTracer) locked(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+// done will record the total duration
+func (s *statsTracer) done() {
+	s.locked(func() { s.stats.Total = time.Since(s.start) })
+}
@@ -0,0 +1,86 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestConfig_SendWithStats will test the option WithStats()
+func TestConfig_SendWithStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	msg := &Message{From: "from", To: []string{to}, Subject: "amazon SES stats test", TextBody: textBody}
+
+	result, err := cfg.Send(context.Background(), msg, WithStats())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stats == nil {
+		t.Fatal("expected stats to be set")
+	}
+	if result.Stats.TTFB <= 0 || result.Stats.Total < result.Stats.TTFB {
+		t.Errorf("unexpected timings: %+v", result.Stats)
+	}
+
+	// No stats unless requested
+	if result, err = cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	} else if result.Stats != nil {
+		t.Errorf("expected no stats, got %+v", result.Stats)
+	}
+}
+
+// TestConfig_SendWithStatsError will test the stats are returned alongside an error response
+func TestConfig_SendWithStatsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	cfg := Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	msg := &Message{From: "from", To: []string{to}, Subject: "amazon SES stats test", TextBody: textBody}
+
+	result, err := cfg.Send(context.Background(), msg, WithStats())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if result == nil || result.Stats == nil {
+		t.Fatal("expected stats alongside the error")
+	}
+	if result.Stats.TTFB <= 0 || result.Stats.Total < result.Stats.TTFB {
+		t.Errorf("unexpected timings: %+v", result.Stats)
+	}
+
+	// No result unless stats were requested
+	if result, err = cfg.Send(context.Background(), msg); err == nil || result != nil {
+		t.Errorf("expected an error without a result, got %+v %v", result, err)
+	}
+}
+
+// TestStatsTracer_ConcurrentDials will test only the first established connection is recorded
+func TestStatsTracer_ConcurrentDials(t *testing.T) {
+	stats := &Stats{}
+	trace := newStatsTracer(stats).clientTrace()
+
+	var wg sync.WaitGroup
+	for _, addr := range []string{"127.0.0.1:443", "[::1]:443"} {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			trace.ConnectStart("tcp", addr)
+			trace.ConnectDone("tcp", addr, nil)
+		}(addr)
+	}
+	wg.Wait()
+	trace.ConnectDone("tcp", "10.0.0.1:443", errors.New("refused"))
+
+	if stats.Connect <= 0 {
+		t.Errorf("expected the connect time to be recorded, got %v", stats.Connect)
+	}
+}
@@ -0,0 +1,86 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+)
+
+// Template is an SES email template (see CreateTemplate / UpdateTemplate)
+type Template struct {
+	// Name is the name of the template
+	Name string `json:"TemplateName" xml:"TemplateName"`
+
+	// SubjectPart is the subject line, can contain {{handlebars}}
+	SubjectPart string `json:"SubjectPart" xml:"SubjectPart"`
+
+	// TextPart is the plain text body, can contain {{handlebars}}
+	TextPart string `json:"TextPart,omitempty" xml:"TextPart"`
+
+	// HTMLPart is the html body, can contain {{handlebars}}
+	HTMLPart string `json:"HtmlPart,omitempty" xml:"HtmlPart"`
+}
+
+// CreateTemplate will create the template (v1 API)
+func (c *Config) CreateTemplate(ctx context.Context, t *Template) error {
+	return c.callQuery(ctx, templateValues("CreateTemplate", t), nil)
+}
+
+// UpdateTemplate will replace the template (v1 API)
+func (c *Config) UpdateTemplate(ctx context.Context, t *Template) error {
+	return c.callQuery(ctx, templateValues("UpdateTemplate", t), nil)
+}
+
+// DeleteTemplate will delete the template (v1 API)
+func (c *Config) DeleteTemplate(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteTemplate")
+	data.Add("TemplateName", name)
+	return c.callQuery(ctx, data, nil)
+}
+
+// GetTemplate returns the template (v1 API)
+func (c *Config) GetTemplate(ctx context.Context, name string) (*Template, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetTemplate")
+	data.Add("TemplateName", name)
+	var out struct {
+		Template *Template `xml:"GetTemplateResult>Template"`
+	}
+	if err := c.callQuery(ctx, data, &out); err != nil {
+		return nil, err
+	}
+	return out.Template, nil
+}
+
+// ListTemplates returns the names of all the templates (v1 API)
+func (c *Config) ListTemplates(ctx context.Context) ([]string, error) {
+	var names []string
+	nextToken := ""
+	for {
+		data := make(url.Values)
+		data.Add("Action", "ListTemplates")
+		addOptional(data, "NextToken", nextToken)
+		var out struct {
+			Names     []string `xml:"ListTemplatesResult>TemplatesMetadata>member>Name"`
+			NextToken string   `xml:"ListTemplatesResult>NextToken"`
+		}
+		if err := c.callQuery(ctx, data, &out); err != nil {
+			return nil, err
+		}
+		names = append(names, out.Names...)
+		if nextToken = out.NextToken; len(nextToken) == 0 {
+			return names, nil
+		}
+	}
+}
+
+// templateValues encodes the template for the create and update actions
+func templateValues(action string, t *Template) url.Values {
+	data := make(url.Values)
+	data.Add("Action", action)
+	data.Add("Template.TemplateName", t.Name)
+	data.Add("Template.SubjectPart", t.SubjectPart)
+	addOptional(data, "Template.TextPart", t.TextPart)
+	addOptional(data, "Template.HtmlPart", t.HTMLPart)
+	return data
+}
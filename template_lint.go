@@ -0,0 +1,162 @@
+package ses
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lint severities
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// defaultMaxHTMLSize is where Gmail starts clipping messages (~102KB)
+const defaultMaxHTMLSize = 100 * 1024
+
+// templateHelpers are the handlebars helpers supported by SES
+var templateHelpers = map[string]bool{
+	"each":   true,
+	"else":   true,
+	"if":     true,
+	"lookup": true,
+	"unless": true,
+	"with":   true,
+}
+
+// handlebarsPattern matches a handlebars expression
+var handlebarsPattern = regexp.MustCompile(`{{{?\s*([#/^~]?)\s*([^\s}]*)([^}]*)}}}?`)
+
+// LintIssue is a single problem found in a template
+type LintIssue struct {
+	Severity string // LintError or LintWarning
+	Part     string // Subject, Text or HTML
+	Message  string
+}
+
+// String returns the issue in a readable (CI log) format
+func (l LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", l.Severity, l.Part, l.Message)
+}
+
+// LintOptions configures LintTemplate()
+type LintOptions struct {
+	// MaxHTMLSize is the size (bytes) of the html part that is flagged (default 100KB)
+	MaxHTMLSize int
+
+	// UnsubscribePlaceholder is the placeholder that the html or text part must contain
+	// (default {{amazonSESUnsubscribeUrl}}), set SkipUnsubscribe for transactional templates
+	UnsubscribePlaceholder string
+
+	// SkipUnsubscribe disables the unsubscribe placeholder check
+	SkipUnsubscribe bool
+
+	// Helpers are additional helper names that are allowed
+	Helpers []string
+}
+
+// LintTemplate checks a template for common rendering hazards (unclosed handlebars,
+// undefined helpers, oversized html, missing unsubscribe placeholder and characters
+// that break SES rendering). Run it in CI before CreateTemplate or UpdateTemplate
+func LintTemplate(t *Template, opts *LintOptions) (issues []LintIssue) {
+	if opts == nil {
+		opts = &LintOptions{}
+	}
+	helpers := make(map[string]bool, len(templateHelpers)+len(opts.Helpers))
+	for name := range templateHelpers {
+		helpers[name] = true
+	}
+	for _, name := range opts.Helpers {
+		helpers[name] = true
+	}
+
+	if len(t.SubjectPart) == 0 {
+		issues = append(issues, LintIssue{LintError, "Subject", "subject part is empty"})
+	}
+	if len(t.TextPart) == 0 && len(t.HTMLPart) == 0 {
+		issues = append(issues, LintIssue{LintError, "Text", "text and html parts are empty"})
+	}
+
+	for _, part := range []struct{ name, content string }{
+		{"Subject", t.SubjectPart}, {"Text", t.TextPart}, {"HTML", t.HTMLPart},
+	} {
+		issues = append(issues, lintPart(part.name, part.content, helpers)...)
+	}
+
+	maxSize := opts.MaxHTMLSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxHTMLSize
+	}
+	if len(t.HTMLPart) > maxSize {
+		issues = append(issues, LintIssue{LintWarning, "HTML", fmt.Sprintf(
+			"html part is %d bytes, larger than %d bytes (clipped by some clients)", len(t.HTMLPart), maxSize,
+		)})
+	}
+
+	if !opts.SkipUnsubscribe {
+		placeholder := opts.UnsubscribePlaceholder
+		if len(placeholder) == 0 {
+			placeholder = "{{amazonSESUnsubscribeUrl}}"
+		}
+		if !strings.Contains(t.HTMLPart, placeholder) && !strings.Contains(t.TextPart, placeholder) {
+			issues = append(issues, LintIssue{LintWarning, "HTML", "missing unsubscribe placeholder " + placeholder})
+		}
+	}
+	return
+}
+
+// lintPart checks the handlebars and characters of a single part
+func lintPart(part, content string, helpers map[string]bool) (issues []LintIssue) {
+	if !utf8.ValidString(content) {
+		issues = append(issues, LintIssue{LintError, part, "contains invalid utf-8"})
+	}
+	for i, r := range content {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			issues = append(issues, LintIssue{LintError, part, fmt.Sprintf("control character %U at byte %d", r, i)})
+			break
+		}
+	}
+
+	// Every opening brace pair needs a closing pair
+	if opened, closed := strings.Count(content, "{{"), strings.Count(content, "}}"); opened != closed {
+		issues = append(issues, LintIssue{LintError, part, fmt.Sprintf(
+			"unbalanced handlebars: %d opening {{ and %d closing }}", opened, closed,
+		)})
+	}
+
+	// Check the helpers and block nesting
+	var blocks []string
+	for _, match := range handlebarsPattern.FindAllStringSubmatch(content, -1) {
+		prefix, name, args := match[1], match[2], strings.TrimSpace(match[3])
+		if strings.HasPrefix(name, "!") { // comment
+			continue
+		}
+		if strings.ContainsAny(match[0], "“”‘’") {
+			issues = append(issues, LintIssue{LintError, part, "curly quotes inside " + match[0]})
+		}
+		switch prefix {
+		case "#":
+			if !helpers[name] {
+				issues = append(issues, LintIssue{LintError, part, "undefined helper " + name})
+			}
+			blocks = append(blocks, name)
+		case "/":
+			if len(blocks) == 0 || blocks[len(blocks)-1] != name {
+				issues = append(issues, LintIssue{LintError, part, "unexpected closing block {{/" + name + "}}"})
+				continue
+			}
+			blocks = blocks[:len(blocks)-1]
+		default:
+			if len(args) > 0 && !helpers[name] {
+				issues = append(issues, LintIssue{LintError, part, "undefined helper " + name})
+			}
+		}
+	}
+	for _, name := range blocks {
+		issues = append(issues, LintIssue{LintError, part, "unclosed block {{#" + name + "}}"})
+	}
+	return
+}
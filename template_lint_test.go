@@ -0,0 +1,59 @@
+package ses
+
+import (
+	"strings"
+	"testing"
+)
+
+// hasIssue returns true if an issue contains the message
+func hasIssue(issues []LintIssue, message string) bool {
+	for _, issue := range issues {
+		if strings.Contains(issue.Message, message) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestLintTemplate will test the method LintTemplate()
+func TestLintTemplate(t *testing.T) {
+	valid := &Template{
+		Name:        "welcome",
+		SubjectPart: "Welcome {{name}}",
+		TextPart:    "Hi {{name}} {{! comment }}",
+		HTMLPart: "<p>{{#if premium}}Thanks{{else}}Upgrade{{/if}}</p>" +
+			"{{#each items}}<li>{{title}}</li>{{/each}}<a href=\"{{amazonSESUnsubscribeUrl}}\">unsubscribe</a>",
+	}
+	if issues := LintTemplate(valid, nil); len(issues) > 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+
+	tests := []struct {
+		name     string
+		template *Template
+		opts     *LintOptions
+		expected string
+	}{
+		{"unclosed expression", &Template{SubjectPart: "Hi {{name", TextPart: "x"}, &LintOptions{SkipUnsubscribe: true}, "unbalanced handlebars"},
+		{"unclosed block", &Template{SubjectPart: "Hi", TextPart: "{{#if a}}x"}, &LintOptions{SkipUnsubscribe: true}, "unclosed block {{#if}}"},
+		{"wrong closing block", &Template{SubjectPart: "Hi", TextPart: "{{#if a}}x{{/each}}"}, &LintOptions{SkipUnsubscribe: true}, "unexpected closing block"},
+		{"undefined block helper", &Template{SubjectPart: "Hi", TextPart: "{{#loop a}}x{{/loop}}"}, &LintOptions{SkipUnsubscribe: true}, "undefined helper loop"},
+		{"undefined helper", &Template{SubjectPart: "Hi", TextPart: "{{upper name}}"}, &LintOptions{SkipUnsubscribe: true}, "undefined helper upper"},
+		{"curly quotes", &Template{SubjectPart: "Hi", TextPart: "{{lookup “name”}}"}, &LintOptions{SkipUnsubscribe: true}, "curly quotes"},
+		{"control character", &Template{SubjectPart: "Hi\x00", TextPart: "x"}, &LintOptions{SkipUnsubscribe: true}, "control character"},
+		{"large html", &Template{SubjectPart: "Hi", HTMLPart: strings.Repeat("a", 11)}, &LintOptions{SkipUnsubscribe: true, MaxHTMLSize: 10}, "larger than 10 bytes"},
+		{"missing unsubscribe", &Template{SubjectPart: "Hi", TextPart: "x"}, nil, "missing unsubscribe"},
+		{"empty", &Template{}, nil, "subject part is empty"},
+	}
+	for _, test := range tests {
+		if issues := LintTemplate(test.template, test.opts); !hasIssue(issues, test.expected) {
+			t.Errorf("%s: expected issue %q, got %v", test.name, test.expected, issues)
+		}
+	}
+
+	// Custom helpers
+	custom := &Template{SubjectPart: "Hi", TextPart: "{{upper name}}"}
+	if issues := LintTemplate(custom, &LintOptions{SkipUnsubscribe: true, Helpers: []string{"upper"}}); len(issues) > 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
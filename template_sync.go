@@ -0,0 +1,178 @@
+//go:build go1.16
+// +build go1.16
+
+package ses
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidTemplateFile is returned for a template file without a subject or a body, or with unknown fields
+var ErrInvalidTemplateFile = errors.New("invalid template file")
+
+// SyncTemplatesOptions are the options for SyncTemplates()
+type SyncTemplatesOptions struct {
+	// DryRun only computes the plan, no template is changed
+	DryRun bool
+
+	// Prune deletes the SES templates that have no local file
+	Prune bool
+}
+
+// TemplatePlan is the list of template changes made (or planned) by SyncTemplates()
+type TemplatePlan struct {
+	Create []string
+	Update []string
+	Delete []string
+}
+
+// String returns the plan output, one "+ create", "~ update" or "- delete" line per template
+func (p *TemplatePlan) String() string {
+	var b strings.Builder
+	for _, change := range []struct {
+		sign  string
+		names []string
+	}{{"+", p.Create}, {"~", p.Update}, {"-", p.Delete}} {
+		for _, name := range change.names {
+			b.WriteString(change.sign + " " + name + "\n")
+		}
+	}
+	return b.String()
+}
+
+// SyncTemplates will make the SES templates match the *.json template files at the root
+// of fsys, in the AWS CLI format ({"Template": {"TemplateName", "SubjectPart", "TextPart",
+// "HtmlPart"}}) or with the template fields only (the name defaults to the file name).
+// Every file needs a subject and a text or html body. Templates are created or updated
+// only when different
+func (c *Config) SyncTemplates(ctx context.Context, fsys fs.FS, opts *SyncTemplatesOptions) (*TemplatePlan, error) {
+	if opts == nil {
+		opts = &SyncTemplatesOptions{}
+	}
+	local, err := readTemplates(fsys)
+	if err != nil {
+		return nil, err
+	}
+	names, err := c.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the plan
+	plan := &TemplatePlan{}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+		if _, ok := local[name]; !ok && opts.Prune {
+			plan.Delete = append(plan.Delete, name)
+		}
+	}
+	for _, name := range sortedTemplateNames(local) {
+		if !existing[name] {
+			plan.Create = append(plan.Create, name)
+			continue
+		}
+		remote, err := c.GetTemplate(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if remote == nil || *remote != *local[name] {
+			plan.Update = append(plan.Update, name)
+		}
+	}
+	sort.Strings(plan.Delete)
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	// Apply the plan
+	for _, name := range plan.Create {
+		if err = c.CreateTemplate(ctx, local[name]); err != nil {
+			return plan, fmt.Errorf("create template %s: %w", name, err)
+		}
+	}
+	for _, name := range plan.Update {
+		if err = c.UpdateTemplate(ctx, local[name]); err != nil {
+			return plan, fmt.Errorf("update template %s: %w", name, err)
+		}
+	}
+	for _, name := range plan.Delete {
+		if err = c.DeleteTemplate(ctx, name); err != nil {
+			return plan, fmt.Errorf("delete template %s: %w", name, err)
+		}
+	}
+	return plan, nil
+}
+
+// readTemplates reads the *.json template files at the root of fsys
+func readTemplates(fsys fs.FS) (map[string]*Template, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]*Template, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		t, err := decodeTemplate(data)
+		if err != nil {
+			return nil, fmt.Errorf("template file %s: %w", file, err)
+		}
+		if len(t.Name) == 0 {
+			t.Name = strings.TrimSuffix(path.Base(file), ".json")
+		}
+		if _, ok := templates[t.Name]; ok {
+			return nil, fmt.Errorf("template file %s: duplicate template %s", file, t.Name)
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+// decodeTemplate decodes a template file, with or without the {"Template": {...}} wrapper
+// of the AWS CLI
+func decodeTemplate(data []byte) (*Template, error) {
+	var wrapper struct {
+		Template json.RawMessage
+	}
+	if err := strictUnmarshal(data, &wrapper); err == nil && wrapper.Template != nil {
+		data = wrapper.Template
+	}
+	t := &Template{}
+	if err := strictUnmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplateFile, err)
+	}
+	if len(t.SubjectPart) == 0 {
+		return nil, fmt.Errorf("%w: missing SubjectPart", ErrInvalidTemplateFile)
+	} else if len(t.TextPart) == 0 && len(t.HTMLPart) == 0 {
+		return nil, fmt.Errorf("%w: missing TextPart or HtmlPart", ErrInvalidTemplateFile)
+	}
+	return t, nil
+}
+
+// strictUnmarshal decodes the JSON data, failing on fields that are not in v
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// sortedTemplateNames returns the template names in order
+func sortedTemplateNames(templates map[string]*Template) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
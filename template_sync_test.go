@@ -0,0 +1,91 @@
+//go:build go1.16
+// +build go1.16
+
+package ses
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestConfig_SyncTemplates will test the method SyncTemplates()
+func TestConfig_SyncTemplates(t *testing.T) {
+	cfg, fake, done := newTemplateConfig(map[string]Template{
+		"same":    {Name: "same", SubjectPart: "Same", TextPart: "same"},
+		"changed": {Name: "changed", SubjectPart: "Old", TextPart: "old"},
+		"removed": {Name: "removed", SubjectPart: "Removed", TextPart: "removed"},
+	})
+	defer done()
+
+	fsys := fstest.MapFS{
+		"same.json":    {Data: []byte(`{"SubjectPart": "Same", "TextPart": "same"}`)},
+		"changed.json": {Data: []byte(`{"TemplateName": "changed", "SubjectPart": "New", "HtmlPart": "<p>new</p>"}`)},
+		"new.json":     {Data: []byte(`{"Template": {"TemplateName": "new", "SubjectPart": "New", "TextPart": "new"}}`)},
+		"README.md":    {Data: []byte("not a template")},
+	}
+
+	// Dry-run does not change anything
+	plan, err := cfg.SyncTemplates(context.Background(), fsys, &SyncTemplatesOptions{DryRun: true, Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.String() != "+ new\n~ changed\n- removed\n" {
+		t.Errorf("Wrong plan:\n%s", plan)
+	}
+	for _, action := range fake.actions {
+		if action != "ListTemplates" && action != "GetTemplate" {
+			t.Errorf("Unexpected action during dry-run: %s", action)
+		}
+	}
+
+	// Without pruning the removed template is kept
+	if plan, err = cfg.SyncTemplates(context.Background(), fsys, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Delete) != 0 || fake.templates["changed"].HTMLPart != "<p>new</p>" || fake.templates["new"].TextPart != "new" {
+		t.Errorf("Wrong result %+v: %+v", plan, fake.templates)
+	}
+
+	// Nothing left but the prune
+	if plan, err = cfg.SyncTemplates(context.Background(), fsys, &SyncTemplatesOptions{Prune: true}); err != nil {
+		t.Fatal(err)
+	}
+	if plan.String() != "- removed\n" || len(fake.templates) != 3 {
+		t.Errorf("Wrong result %q: %+v", plan, fake.templates)
+	}
+
+	// Invalid files
+	fsys["bad.json"] = &fstest.MapFile{Data: []byte("{")}
+	if _, err = cfg.SyncTemplates(context.Background(), fsys, nil); err == nil || !strings.Contains(err.Error(), "bad.json") {
+		t.Errorf("Expected an error for bad.json got %v", err)
+	}
+}
+
+// TestReadTemplates_Invalid will test incomplete or unknown template files are rejected before planning
+func TestReadTemplates_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no subject":      `{"TemplateName": "welcome", "TextPart": "hi"}`,
+		"no body":         `{"TemplateName": "welcome", "SubjectPart": "Hi"}`,
+		"empty wrapper":   `{"Template": {}}`,
+		"unknown field":   `{"SubjectPart": "Hi", "TextPart": "hi", "Body": "hi"}`,
+		"unknown wrapper": `{"Templates": {"SubjectPart": "Hi", "TextPart": "hi"}}`,
+	} {
+		if _, err := readTemplates(fstest.MapFS{"welcome.json": {Data: []byte(data)}}); !errors.Is(err, ErrInvalidTemplateFile) {
+			t.Errorf("%s: expected %v, got %v", name, ErrInvalidTemplateFile, err)
+		}
+	}
+
+	// The AWS CLI format
+	templates, err := readTemplates(fstest.MapFS{"file.json": {Data: []byte(
+		`{"Template": {"TemplateName": "welcome", "SubjectPart": "Hi", "HtmlPart": "<p>hi</p>"}}`,
+	)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if welcome := templates["welcome"]; welcome == nil || welcome.SubjectPart != "Hi" || welcome.HTMLPart != "<p>hi</p>" {
+		t.Errorf("Wrong templates: %+v", templates)
+	}
+}
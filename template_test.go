@@ -0,0 +1,106 @@
+package ses
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// templateServer is a fake SES storing the templates in memory
+type templateServer struct {
+	mu        sync.Mutex
+	templates map[string]Template
+	actions   []string
+}
+
+// ServeHTTP answers the template actions
+func (s *templateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	action, name := r.PostForm.Get("Action"), r.PostForm.Get("TemplateName")
+	s.actions = append(s.actions, action)
+	switch action {
+	case "CreateTemplate", "UpdateTemplate":
+		s.templates[r.PostForm.Get("Template.TemplateName")] = Template{
+			Name:        r.PostForm.Get("Template.TemplateName"),
+			SubjectPart: r.PostForm.Get("Template.SubjectPart"),
+			TextPart:    r.PostForm.Get("Template.TextPart"),
+			HTMLPart:    r.PostForm.Get("Template.HtmlPart"),
+		}
+	case "DeleteTemplate":
+		delete(s.templates, name)
+	case "GetTemplate":
+		t := s.templates[name]
+		out, _ := xml.Marshal(struct {
+			XMLName  xml.Name  `xml:"GetTemplateResponse"`
+			Template *Template `xml:"GetTemplateResult>Template"`
+		}{Template: &t})
+		_, _ = w.Write(out)
+	case "ListTemplates":
+		var list struct {
+			XMLName xml.Name `xml:"ListTemplatesResponse"`
+			Names   []string `xml:"ListTemplatesResult>TemplatesMetadata>member>Name"`
+		}
+		list.Names = sortedTemplateNames(s.pointers())
+		out, _ := xml.Marshal(list)
+		_, _ = w.Write(out)
+	}
+}
+
+// pointers returns the templates by name
+func (s *templateServer) pointers() map[string]*Template {
+	templates := make(map[string]*Template, len(s.templates))
+	for name := range s.templates {
+		t := s.templates[name]
+		templates[name] = &t
+	}
+	return templates
+}
+
+// newTemplateConfig returns a Config for a fake template server
+func newTemplateConfig(templates map[string]Template) (*Config, *templateServer, func()) {
+	fake := &templateServer{templates: templates}
+	server := httptest.NewServer(fake)
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	return cfg, fake, server.Close
+}
+
+// TestConfig_Templates will test the template API calls
+func TestConfig_Templates(t *testing.T) {
+	cfg, _, done := newTemplateConfig(map[string]Template{})
+	defer done()
+	ctx := context.Background()
+
+	welcome := &Template{Name: "welcome", SubjectPart: "Hi {{name}}", TextPart: "Welcome", HTMLPart: "<p>Welcome</p>"}
+	if err := cfg.CreateTemplate(ctx, welcome); err != nil {
+		t.Fatal(err)
+	}
+	welcome.TextPart = "Welcome!"
+	if err := cfg.UpdateTemplate(ctx, welcome); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.GetTemplate(ctx, "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *welcome {
+		t.Errorf("Expected %+v got %+v", welcome, got)
+	}
+	names, err := cfg.ListTemplates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "welcome" {
+		t.Errorf("Wrong names: %v", names)
+	}
+	if err = cfg.DeleteTemplate(ctx, "welcome"); err != nil {
+		t.Fatal(err)
+	}
+	if names, _ = cfg.ListTemplates(ctx); len(names) != 0 {
+		t.Errorf("Expected no templates: %v", names)
+	}
+}
@@ -0,0 +1,53 @@
+package ses
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// SetInReplyTo will set the In-Reply-To header to the Message-ID being replied to
+func (m *RawMessage) SetInReplyTo(messageID string) error {
+	return m.setHeader("In-Reply-To", messageID)
+}
+
+// SetReferences will set the References header (oldest Message-ID first)
+func (m *RawMessage) SetReferences(messageIDs ...string) error {
+	return m.setHeader("References", strings.Join(messageIDs, " "))
+}
+
+// ContinueThread will set the In-Reply-To and References headers so the raw message
+// threads as a reply to the parsed inbound message. The Subject is set to "Re: <subject>"
+// if the raw message does not have one
+func (m *RawMessage) ContinueThread(parent *mail.Message) error {
+	id := strings.TrimSpace(parent.Header.Get("Message-ID"))
+	if len(id) == 0 {
+		return nil
+	}
+	references := strings.Fields(parent.Header.Get("References"))
+	if len(references) == 0 {
+		references = strings.Fields(parent.Header.Get("In-Reply-To"))
+	}
+
+	parts := splitRaw(m.Data)
+	if err := parts.set("In-Reply-To", id); err != nil {
+		return err
+	}
+	if err := parts.set("References", strings.Join(append(references, id), " ")); err != nil {
+		return err
+	}
+	if len(parts.get("Subject")) == 0 {
+		if err := parts.set("Subject", replySubject(parent.Header.Get("Subject"))); err != nil {
+			return err
+		}
+	}
+	m.Data = parts.bytes()
+	return nil
+}
+
+// replySubject prefixes the subject with "Re: " (once)
+func replySubject(subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
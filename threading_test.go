@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// TestRawMessage_SetInReplyTo will test the threading header helpers
+func TestRawMessage_SetInReplyTo(t *testing.T) {
+	msg := &RawMessage{Data: []byte("From: from@example.com\r\n\r\nbody")}
+	if err := msg.SetInReplyTo("<b@example.com>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.SetReferences("<a@example.com>", "<b@example.com>"); err != nil {
+		t.Fatal(err)
+	}
+
+	parts := splitRaw(msg.Data)
+	if parts.get("In-Reply-To") != "<b@example.com>" {
+		t.Errorf("Wrong In-Reply-To: %s", parts.get("In-Reply-To"))
+	}
+	if parts.get("References") != "<a@example.com> <b@example.com>" {
+		t.Errorf("Wrong References: %s", parts.get("References"))
+	}
+}
+
+// TestRawMessage_ThreadingInjection will test the threading headers can't inject other headers
+func TestRawMessage_ThreadingInjection(t *testing.T) {
+	data := []byte("From: from@example.com\r\n\r\nbody")
+	msg := &RawMessage{Data: data}
+	if err := msg.SetInReplyTo("<b@example.com>\r\nBcc: victim@example.com"); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected %v, got %v", ErrInvalidHeader, err)
+	}
+	if err := msg.SetReferences("<a@example.com>", "<b@example.com>\nBcc: victim@example.com"); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected %v, got %v", ErrInvalidHeader, err)
+	}
+	parent := &mail.Message{Header: mail.Header{"Message-Id": []string{"<c@example.com>\r\nBcc: victim@example.com"}}}
+	if err := msg.ContinueThread(parent); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected %v, got %v", ErrInvalidHeader, err)
+	}
+	if string(msg.Data) != string(data) {
+		t.Errorf("expected the message to be unchanged: %q", msg.Data)
+	}
+}
+
+// TestRawMessage_ContinueThread will test replying to a parsed inbound message
+func TestRawMessage_ContinueThread(t *testing.T) {
+	inbound := "Message-ID: <c@example.com>\r\nReferences: <a@example.com> <b@example.com>\r\n" +
+		"Subject: Order 42\r\n\r\nhello"
+	parent, err := mail.ReadMessage(strings.NewReader(inbound))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := &RawMessage{Data: []byte("From: from@example.com\r\n\r\nbody")}
+	if err = msg.ContinueThread(parent); err != nil {
+		t.Fatal(err)
+	}
+	parts := splitRaw(msg.Data)
+	if parts.get("In-Reply-To") != "<c@example.com>" {
+		t.Errorf("Wrong In-Reply-To: %s", parts.get("In-Reply-To"))
+	}
+	if parts.get("References") != "<a@example.com> <b@example.com> <c@example.com>" {
+		t.Errorf("Wrong References: %s", parts.get("References"))
+	}
+	if parts.get("Subject") != "Re: Order 42" {
+		t.Errorf("Wrong Subject: %s", parts.get("Subject"))
+	}
+
+	// Keeps the subject and does not stack prefixes
+	msg = &RawMessage{Data: []byte("Subject: Thanks\r\n\r\nbody")}
+	if err = msg.ContinueThread(parent); err != nil {
+		t.Fatal(err)
+	}
+	if splitRaw(msg.Data).get("Subject") != "Thanks" {
+		t.Errorf("Expected the subject to be kept: %s", msg.Data)
+	}
+	if replySubject("RE: Order 42") != "RE: Order 42" {
+		t.Errorf("Expected a single prefix")
+	}
+}
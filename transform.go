@@ -0,0 +1,264 @@
+package ses
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BodyTransformer rewrites the HTML body of a message before it is sent (CSS inlining,
+// MJML compilation, link rewriting...)
+type BodyTransformer interface {
+	TransformHTML(html string) (string, error)
+}
+
+// BodyTransformerFunc is a function that implements BodyTransformer
+type BodyTransformerFunc func(html string) (string, error)
+
+// TransformHTML returns the transformed html
+func (f BodyTransformerFunc) TransformHTML(html string) (string, error) {
+	return f(html)
+}
+
+// transformHTML will run the html through the transformers, in order
+func transformHTML(html string, transformers []BodyTransformer) (string, error) {
+	var err error
+	for _, transformer := range transformers {
+		if html, err = transformer.TransformHTML(html); err != nil {
+			return "", err
+		}
+	}
+	return html, nil
+}
+
+var (
+	styleBlockPattern  = regexp.MustCompile(`(?is)<style[^>]*>(.*?)</style>`)
+	cssCommentPattern  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	openTagPattern     = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^>]*?)?)(/?)>`)
+	simpleSelector     = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9]*)?((?:[.#][\w-]+)*)$`)
+	selectorPartsRegex = regexp.MustCompile(`[.#][\w-]+`)
+
+	// attributePatterns are the patterns of the quoted attributes read or set by the inliner
+	attributePatterns = map[string]*regexp.Regexp{
+		"class": newAttributePattern("class"),
+		"id":    newAttributePattern("id"),
+		"style": newAttributePattern("style"),
+	}
+)
+
+// CSSInliner is a reference BodyTransformer that moves the rules of <style> blocks into
+// style attributes, which most email clients require. It supports simple selectors (tag,
+// .class, #id and combinations like p.note, comma separated); other rules (@media,
+// pseudo-classes, descendant selectors) are kept in a <style> block
+type CSSInliner struct {
+	// KeepStyleBlocks keeps the original <style> blocks in addition to inlining
+	KeepStyleBlocks bool
+}
+
+// cssRule is a simple-selector rule
+type cssRule struct {
+	tag          string
+	ids          []string
+	classes      []string
+	specificity  int
+	order        int
+	declarations []string
+}
+
+// matches returns true if the rule selects the element
+func (r *cssRule) matches(tag, id string, classes map[string]bool) bool {
+	if len(r.tag) > 0 && !strings.EqualFold(r.tag, tag) {
+		return false
+	}
+	for _, want := range r.ids {
+		if want != id {
+			return false
+		}
+	}
+	for _, class := range r.classes {
+		if !classes[class] {
+			return false
+		}
+	}
+	return true
+}
+
+// TransformHTML returns the html with the styles inlined
+func (c *CSSInliner) TransformHTML(html string) (string, error) {
+	var rules []*cssRule
+	var kept []string
+	for _, block := range styleBlockPattern.FindAllStringSubmatch(html, -1) {
+		blockRules, blockKept := parseCSS(block[1], len(rules))
+		rules = append(rules, blockRules...)
+		kept = append(kept, blockKept...)
+	}
+	if len(rules) == 0 {
+		return html, nil
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity < rules[j].specificity
+		}
+		return rules[i].order < rules[j].order
+	})
+
+	// Replace the style blocks with the rules that can't be inlined
+	if !c.KeepStyleBlocks {
+		first := true
+		html = styleBlockPattern.ReplaceAllStringFunc(html, func(string) string {
+			if !first || len(kept) == 0 {
+				return ""
+			}
+			first = false
+			return "<style>" + strings.Join(kept, "\n") + "</style>"
+		})
+	}
+
+	// Inline the rules (the existing style attributes win)
+	return openTagPattern.ReplaceAllStringFunc(html, func(tag string) string {
+		if strings.HasPrefix(strings.ToLower(tag), "<style") {
+			return tag
+		}
+		m := openTagPattern.FindStringSubmatch(tag)
+		attributes := m[2]
+		classes := make(map[string]bool)
+		for _, class := range strings.Fields(attributeValue(attributes, "class")) {
+			classes[class] = true
+		}
+		id := attributeValue(attributes, "id")
+
+		var declarations []string
+		for _, rule := range rules {
+			if rule.matches(m[1], id, classes) {
+				declarations = append(declarations, rule.declarations...)
+			}
+		}
+		if len(declarations) == 0 {
+			return tag
+		}
+		declarations = append(declarations, splitDeclarations(attributeValue(attributes, "style"))...)
+		style := strings.Join(mergeDeclarations(declarations), "; ")
+		return "<" + m[1] + setAttribute(attributes, "style", style) + m[3] + ">"
+	}), nil
+}
+
+// parseCSS returns the simple-selector rules and the raw rules that can't be inlined
+func parseCSS(css string, order int) (rules []*cssRule, kept []string) {
+	css = cssCommentPattern.ReplaceAllString(css, "")
+	for len(strings.TrimSpace(css)) > 0 {
+		open := strings.Index(css, "{")
+		if open < 0 {
+			break
+		}
+		selectors := strings.TrimSpace(css[:open])
+
+		// Find the end of the rule (at-rules can be nested)
+		depth, end := 0, len(css)
+		for i := open; i < len(css); i++ {
+			if css[i] == '{' {
+				depth++
+			} else if css[i] == '}' {
+				if depth--; depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		body, raw := css[open+1:end], strings.TrimSpace(css[:end])+"}"
+		if end < len(css) {
+			raw, css = strings.TrimSpace(css[:end+1]), css[end+1:]
+		} else {
+			css = ""
+		}
+
+		if strings.HasPrefix(selectors, "@") {
+			kept = append(kept, raw)
+			continue
+		}
+		declarations := splitDeclarations(body)
+		var complexSelectors []string
+		for _, selector := range strings.Split(selectors, ",") {
+			selector = strings.TrimSpace(selector)
+			m := simpleSelector.FindStringSubmatch(selector)
+			if len(selector) == 0 || m == nil {
+				complexSelectors = append(complexSelectors, selector)
+				continue
+			}
+			rule := &cssRule{tag: m[1], order: order, declarations: declarations}
+			if len(m[1]) > 0 {
+				rule.specificity = 1
+			}
+			for _, part := range selectorPartsRegex.FindAllString(m[2], -1) {
+				if part[0] == '#' {
+					rule.ids = append(rule.ids, part[1:])
+					rule.specificity += 100
+				} else {
+					rule.classes = append(rule.classes, part[1:])
+					rule.specificity += 10
+				}
+			}
+			rules = append(rules, rule)
+			order++
+		}
+		if len(complexSelectors) > 0 {
+			kept = append(kept, strings.Join(complexSelectors, ", ")+" {"+body+"}")
+		}
+	}
+	return
+}
+
+// splitDeclarations splits "a: 1; b: 2" into its declarations
+func splitDeclarations(style string) (declarations []string) {
+	for _, declaration := range strings.Split(style, ";") {
+		if declaration = strings.TrimSpace(declaration); strings.Contains(declaration, ":") {
+			declarations = append(declarations, declaration)
+		}
+	}
+	return
+}
+
+// mergeDeclarations keeps the last declaration of each property, in the order of their last occurrence
+func mergeDeclarations(declarations []string) []string {
+	last := make(map[string]int, len(declarations))
+	for i, declaration := range declarations {
+		last[strings.ToLower(strings.TrimSpace(declaration[:strings.Index(declaration, ":")]))] = i
+	}
+	merged := make([]string, 0, len(last))
+	for i, declaration := range declarations {
+		if last[strings.ToLower(strings.TrimSpace(declaration[:strings.Index(declaration, ":")]))] == i {
+			merged = append(merged, declaration)
+		}
+	}
+	return merged
+}
+
+// newAttributePattern returns the pattern of a quoted attribute
+func newAttributePattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\s` + name + `\s*=\s*("([^"]*)"|'([^']*)')`)
+}
+
+// attributePattern returns the precompiled pattern of a quoted attribute
+func attributePattern(name string) *regexp.Regexp {
+	if pattern, ok := attributePatterns[name]; ok {
+		return pattern
+	}
+	return newAttributePattern(regexp.QuoteMeta(name))
+}
+
+// attributeValue returns the value of a quoted attribute
+func attributeValue(attributes, name string) string {
+	if m := attributePattern(name).FindStringSubmatch(attributes); m != nil {
+		return m[2] + m[3]
+	}
+	return ""
+}
+
+// setAttribute replaces (or adds) a quoted attribute
+func setAttribute(attributes, name, value string) string {
+	value = strings.ReplaceAll(value, `"`, "&quot;")
+	pattern := attributePattern(name)
+	if pattern.MatchString(attributes) {
+		return pattern.ReplaceAllLiteralString(attributes, " "+name+`="`+value+`"`)
+	}
+	return strings.TrimRight(attributes, " ") + " " + name + `="` + value + `"`
+}
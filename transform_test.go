@@ -0,0 +1,102 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestCSSInliner_TransformHTML will test the reference CSS inliner
+func TestCSSInliner_TransformHTML(t *testing.T) {
+	html := `<html><head><style type="text/css">
+/* brand */
+p { color: red; margin: 0 }
+.note, #footer { color: blue }
+p.note { font-weight: bold }
+a:hover { color: green }
+@media (max-width: 600px) { p { margin: 4px } }
+</style></head><body>
+<p>plain</p>
+<p class="note big" style="margin: 2px">note</p>
+<div id='footer'>footer<br/></div>
+</body></html>`
+
+	out, err := (&CSSInliner{}).TransformHTML(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<p style="color: red; margin: 0">plain</p>`,
+		`<p class="note big" style="color: blue; font-weight: bold; margin: 2px">note</p>`,
+		`<div id='footer' style="color: blue">`,
+		`<br/>`,
+		`<style>a:hover { color: green }` + "\n" + `@media (max-width: 600px) { p { margin: 4px } }</style>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Missing %s in:\n%s", expected, out)
+		}
+	}
+	if strings.Count(out, "<style") != 1 {
+		t.Errorf("Expected a single style block:\n%s", out)
+	}
+
+	// Keeping the style blocks
+	if out, _ = (&CSSInliner{KeepStyleBlocks: true}).TransformHTML(html); !strings.Contains(out, "/* brand */") {
+		t.Errorf("Expected the original style block:\n%s", out)
+	}
+
+	// Nothing to inline
+	if out, _ = (&CSSInliner{}).TransformHTML("<p>hi</p>"); out != "<p>hi</p>" {
+		t.Errorf("Expected no change: %s", out)
+	}
+}
+
+// TestConfig_SendBodyTransformers will test that the transformers run in order before sending
+func TestConfig_SendBodyTransformers(t *testing.T) {
+	var values url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		values = r.PostForm
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient,
+		BodyTransformers: []BodyTransformer{
+			&CSSInliner{},
+			BodyTransformerFunc(func(html string) (string, error) { return html + "<!-- footer -->", nil }),
+		},
+	}
+	msg := &Message{From: "from@example.com", To: []string{to}, Subject: "s", HTMLBody: "<style>p { color: red }</style><p>hi</p>"}
+	if _, err := cfg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("Message.Body.Html.Data") != `<p style="color: red">hi</p><!-- footer -->` {
+		t.Errorf("Wrong html: %s", values.Get("Message.Body.Html.Data"))
+	}
+	if msg.HTMLBody != "<style>p { color: red }</style><p>hi</p>" {
+		t.Errorf("Expected the message to be unchanged")
+	}
+
+	failure := errors.New("failure")
+	cfg.BodyTransformers = []BodyTransformer{BodyTransformerFunc(func(string) (string, error) { return "", failure })}
+	if _, err := cfg.Send(context.Background(), msg); !errors.Is(err, failure) {
+		t.Errorf("Expected the transformer error got %v", err)
+	}
+}
+
+// TestAttributePattern will test the attribute patterns are compiled once
+func TestAttributePattern(t *testing.T) {
+	for _, name := range []string{"class", "id", "style"} {
+		if attributePattern(name) != attributePattern(name) {
+			t.Errorf("expected the %s pattern to be precompiled", name)
+		}
+	}
+	if value := attributeValue(` data-x="1"`, "data-x"); value != "1" {
+		t.Errorf("Wrong value: %s", value)
+	}
+}
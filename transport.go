@@ -0,0 +1,18 @@
+package ses
+
+import "context"
+
+// Transport sends the package message models. Config and IdentityResolver send with SES,
+// alternate backends (SMTP, file, other providers) can implement it and verify their
+// behavior with the transporttest conformance suite. Implementations that have no use
+// for the SendOptions ignore them
+type Transport interface {
+	Send(ctx context.Context, m *Message, opts ...SendOption) (*SendResult, error)
+	SendRaw(ctx context.Context, m *RawMessage, opts ...SendOption) (*SendResult, error)
+}
+
+// The SES transports
+var (
+	_ Transport = (*Config)(nil)
+	_ Transport = (*IdentityResolver)(nil)
+)
@@ -0,0 +1,147 @@
+// Package transporttest is a conformance suite for ses.Transport implementations
+package transporttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mrz1836/go-ses"
+)
+
+// Options configure the conformance suite
+type Options struct {
+	// From is a sender address the transport accepts (default from@example.com)
+	From string
+
+	// To is a recipient address the transport accepts (default to@example.com)
+	To string
+
+	// SkipCanceledContext skips the canceled context test (transports that don't do I/O)
+	SkipCanceledContext bool
+}
+
+// Run runs the conformance suite against the transport returned by newTransport (called
+// once per sub-test, so each test can use a fresh backend)
+func Run(t *testing.T, newTransport func(t *testing.T) ses.Transport, opts *Options) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if len(opts.From) == 0 {
+		opts.From = "from@example.com"
+	}
+	if len(opts.To) == 0 {
+		opts.To = "to@example.com"
+	}
+	raw := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: conformance\r\n\r\nbody", opts.From, opts.To))
+
+	t.Run("Send", func(t *testing.T) {
+		m := &ses.Message{From: opts.From, To: []string{opts.To}, Subject: "conformance", TextBody: "text", HTMLBody: "<p>html</p>"}
+		result, err := newTransport(t).Send(context.Background(), m)
+		if err != nil {
+			t.Fatalf("Send: %s", err)
+		}
+		if result == nil {
+			t.Errorf("Send: expected a result")
+		}
+	})
+
+	t.Run("SendRaw", func(t *testing.T) {
+		result, err := newTransport(t).SendRaw(context.Background(), &ses.RawMessage{Data: raw})
+		if err != nil {
+			t.Fatalf("SendRaw: %s", err)
+		}
+		if result == nil {
+			t.Errorf("SendRaw: expected a result")
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		transport := newTransport(t)
+		tests := []struct {
+			name     string
+			send     func() error
+			expected error
+		}{
+			{"missing from", func() error {
+				_, err := transport.Send(context.Background(), &ses.Message{To: []string{opts.To}, Subject: "s"})
+				return err
+			}, ses.ErrMissingFrom},
+			{"missing recipients", func() error {
+				_, err := transport.Send(context.Background(), &ses.Message{From: opts.From, Subject: "s"})
+				return err
+			}, ses.ErrMissingRecipients},
+			{"missing raw data", func() error {
+				_, err := transport.SendRaw(context.Background(), &ses.RawMessage{})
+				return err
+			}, ses.ErrMissingRawData},
+		}
+		for _, test := range tests {
+			if err := test.send(); !errors.Is(err, test.expected) {
+				t.Errorf("%s: expected %v got %v", test.name, test.expected, err)
+			}
+		}
+	})
+
+	t.Run("Unmodified", func(t *testing.T) {
+		transport := newTransport(t)
+		m := &ses.Message{
+			From: opts.From, To: []string{opts.To}, Subject: "conformance", TextBody: "text",
+			Tags: map[string]string{"suite": "conformance"},
+		}
+		before := *m
+		before.To = append([]string{}, m.To...)
+		before.Tags = map[string]string{"suite": "conformance"}
+		if _, err := transport.Send(context.Background(), m); err != nil {
+			t.Fatalf("Send: %s", err)
+		}
+		if !reflect.DeepEqual(*m, before) {
+			t.Errorf("Send modified the message: %+v", m)
+		}
+
+		r := &ses.RawMessage{Data: append([]byte{}, raw...)}
+		if _, err := transport.SendRaw(context.Background(), r); err != nil {
+			t.Fatalf("SendRaw: %s", err)
+		}
+		if string(r.Data) != string(raw) {
+			t.Errorf("SendRaw modified the message: %s", r.Data)
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		if opts.SkipCanceledContext {
+			t.Skip("skipped by the options")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := newTransport(t).Send(ctx, &ses.Message{From: opts.From, To: []string{opts.To}, Subject: "s"}); err == nil {
+			t.Errorf("Send: expected an error with a canceled context")
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		transport := newTransport(t)
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := transport.Send(context.Background(), &ses.Message{
+					From: opts.From, To: []string{opts.To}, Subject: fmt.Sprintf("concurrent %d", i), TextBody: "text",
+				})
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Errorf("Send: %s", err)
+			}
+		}
+	})
+}
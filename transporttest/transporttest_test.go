@@ -0,0 +1,30 @@
+package transporttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mrz1836/go-ses"
+)
+
+// newConfig returns a Config for a server that accepts every request
+func newConfig(t *testing.T) *ses.Config {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	return &ses.Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+}
+
+// TestConfig will run the suite against the SES Config
+func TestConfig(t *testing.T) {
+	Run(t, func(t *testing.T) ses.Transport { return newConfig(t) }, nil)
+}
+
+// TestIdentityResolver will run the suite against the IdentityResolver
+func TestIdentityResolver(t *testing.T) {
+	Run(t, func(t *testing.T) ses.Transport {
+		resolver := ses.NewIdentityResolver(nil)
+		resolver.Register("example.com", newConfig(t))
+		return resolver
+	}, nil)
+}
@@ -0,0 +1,198 @@
+package ses
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnverifiedSender is returned when the From address is not a verified identity
+var ErrUnverifiedSender = errors.New("from address is not a verified identity")
+
+// maxVerificationIdentities is the max identities per GetIdentityVerificationAttributes call
+const maxVerificationIdentities = 100
+
+// ListIdentities returns all the identities (addresses and domains) of the account (v1 API)
+func (c *Config) ListIdentities(ctx context.Context) ([]string, error) {
+	var identities []string
+	nextToken := ""
+	for {
+		data := make(url.Values)
+		data.Add("Action", "ListIdentities")
+		addOptional(data, "NextToken", nextToken)
+		var out struct {
+			Identities []string `xml:"ListIdentitiesResult>Identities>member"`
+			NextToken  string   `xml:"ListIdentitiesResult>NextToken"`
+		}
+		if err := c.callQuery(ctx, data, &out); err != nil {
+			return nil, err
+		}
+		identities = append(identities, out.Identities...)
+		if nextToken = out.NextToken; len(nextToken) == 0 {
+			return identities, nil
+		}
+	}
+}
+
+// GetIdentityVerificationAttributes returns the verification status (Pending, Success, Failed,
+// TemporaryFailure or NotStarted) of the identities (v1 API)
+func (c *Config) GetIdentityVerificationAttributes(ctx context.Context, identities []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(identities))
+	for start := 0; start < len(identities); start += maxVerificationIdentities {
+		end := start + maxVerificationIdentities
+		if end > len(identities) {
+			end = len(identities)
+		}
+		data := make(url.Values)
+		data.Add("Action", "GetIdentityVerificationAttributes")
+		addMembers(data, "Identities.member", identities[start:end])
+		var out struct {
+			Entries []struct {
+				Key    string `xml:"key"`
+				Status string `xml:"value>VerificationStatus"`
+			} `xml:"GetIdentityVerificationAttributesResult>VerificationAttributes>entry"`
+		}
+		if err := c.callQuery(ctx, data, &out); err != nil {
+			return nil, err
+		}
+		for _, entry := range out.Entries {
+			statuses[entry.Key] = entry.Status
+		}
+	}
+	return statuses, nil
+}
+
+// callQuery fires a v1 query API action and decodes the XML response into out (if not nil)
+func (c *Config) callQuery(ctx context.Context, data url.Values, out interface{}) error {
+	data.Add("AWSAccessKeyId", c.credentials().AccessKeyID)
+	result, err := c.sesPost(ctx, (&QueryMarshaler{}).request(data), newSendOptions(nil))
+	if err != nil || out == nil {
+		return err
+	}
+	return xml.Unmarshal([]byte(result.Body), out)
+}
+
+// VerifiedIdentityCache is a cached set of the verified identities, so sends from an
+// unverified From fail fast with ErrUnverifiedSender instead of using a send attempt.
+// Identities are verified per region, so each region has its own set, listed using the
+// sending Config (it can be shared by Configs of different regions)
+type VerifiedIdentityCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	regions map[string]*identitySet
+}
+
+// identitySet are the verified identities of a region
+type identitySet struct {
+	verified  map[string]bool
+	refreshed time.Time
+}
+
+// NewVerifiedIdentityCache will return a cache of the verified identities, refreshed
+// when older than the ttl (0 never expires)
+func NewVerifiedIdentityCache(ttl time.Duration) *VerifiedIdentityCache {
+	return &VerifiedIdentityCache{ttl: ttl, regions: make(map[string]*identitySet)}
+}
+
+// Refresh will reload the verified identities of the Config region from SES
+func (v *VerifiedIdentityCache) Refresh(ctx context.Context, c *Config) error {
+	identities, err := c.ListIdentities(ctx)
+	if err != nil {
+		return err
+	}
+	statuses, err := c.GetIdentityVerificationAttributes(ctx, identities)
+	if err != nil {
+		return err
+	}
+	verified := make(map[string]bool, len(statuses))
+	for identity, status := range statuses {
+		if status == "Success" {
+			verified[strings.ToLower(identity)] = true
+		}
+	}
+
+	v.mu.Lock()
+	*v.set(c.Region) = identitySet{verified: verified, refreshed: time.Now()}
+	v.mu.Unlock()
+	return nil
+}
+
+// set returns the identities of the region (v.mu must be locked for writing)
+func (v *VerifiedIdentityCache) set(region string) *identitySet {
+	if v.regions == nil {
+		v.regions = make(map[string]*identitySet)
+	}
+	set, ok := v.regions[region]
+	if !ok {
+		set = &identitySet{verified: make(map[string]bool)}
+		v.regions[region] = set
+	}
+	return set
+}
+
+// Add will mark an identity of the region as verified right away (read-your-writes after verifying it)
+func (v *VerifiedIdentityCache) Add(region, identity string) {
+	v.mu.Lock()
+	v.set(region).verified[strings.ToLower(identity)] = true
+	v.mu.Unlock()
+}
+
+// Remove will mark an identity of the region as not verified right away (after deleting it)
+func (v *VerifiedIdentityCache) Remove(region, identity string) {
+	v.mu.Lock()
+	delete(v.set(region).verified, strings.ToLower(identity))
+	v.mu.Unlock()
+}
+
+// stale returns true if the identities of the region have never been loaded or have expired
+func (v *VerifiedIdentityCache) stale(region string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	set, ok := v.regions[region]
+	return !ok || set.refreshed.IsZero() || (v.ttl > 0 && time.Since(set.refreshed) > v.ttl)
+}
+
+// Verified returns true if the address or its domain (or a parent domain) is verified in
+// the Config region. The identities are refreshed first (using the Config) when stale
+func (v *VerifiedIdentityCache) Verified(ctx context.Context, c *Config, address string) (bool, error) {
+	if v.stale(c.Region) {
+		if err := v.Refresh(ctx, c); err != nil {
+			return false, err
+		}
+	}
+	email := strings.ToLower(address)
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	verified := v.regions[c.Region].verified
+	if verified[email] {
+		return true, nil
+	}
+	for _, domain := range domainCandidates(email) {
+		if verified[domain] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Check returns ErrUnverifiedSender if the From address is not verified in the Config region (see Verified)
+func (v *VerifiedIdentityCache) Check(ctx context.Context, c *Config, from string) error {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return err
+	}
+	verified, err := v.Verified(ctx, c, address.Address)
+	if err != nil {
+		return err
+	} else if !verified {
+		return fmt.Errorf("%w: %s", ErrUnverifiedSender, strings.ToLower(address.Address))
+	}
+	return nil
+}
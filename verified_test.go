@@ -0,0 +1,155 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newIdentityServer returns a server answering ListIdentities (two pages) and GetIdentityVerificationAttributes
+func newIdentityServer(calls map[string]int) *httptest.Server {
+	return httptest.NewServer(identityHandler(calls))
+}
+
+// identityHandler answers ListIdentities (two pages) and GetIdentityVerificationAttributes
+func identityHandler(calls map[string]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		action := r.PostForm.Get("Action")
+		calls[action]++
+		switch {
+		case action == "ListIdentities" && len(r.PostForm.Get("NextToken")) == 0:
+			_, _ = w.Write([]byte(`<ListIdentitiesResponse><ListIdentitiesResult><Identities>` +
+				`<member>example.com</member><member>pending.com</member></Identities>` +
+				`<NextToken>page2</NextToken></ListIdentitiesResult></ListIdentitiesResponse>`))
+		case action == "ListIdentities":
+			_, _ = w.Write([]byte(`<ListIdentitiesResponse><ListIdentitiesResult><Identities>` +
+				`<member>jane@gmail.com</member></Identities></ListIdentitiesResult></ListIdentitiesResponse>`))
+		case action == "GetIdentityVerificationAttributes":
+			_, _ = w.Write([]byte(`<GetIdentityVerificationAttributesResponse><GetIdentityVerificationAttributesResult>` +
+				`<VerificationAttributes>` +
+				`<entry><key>example.com</key><value><VerificationStatus>Success</VerificationStatus></value></entry>` +
+				`<entry><key>pending.com</key><value><VerificationStatus>Pending</VerificationStatus></value></entry>` +
+				`<entry><key>jane@gmail.com</key><value><VerificationStatus>Success</VerificationStatus></value></entry>` +
+				`</VerificationAttributes></GetIdentityVerificationAttributesResult></GetIdentityVerificationAttributesResponse>`))
+		}
+	}
+}
+
+// TestConfig_ListIdentities will test the identity API calls
+func TestConfig_ListIdentities(t *testing.T) {
+	calls := make(map[string]int)
+	server := newIdentityServer(calls)
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	identities, err := cfg.ListIdentities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 3 || calls["ListIdentities"] != 2 {
+		t.Errorf("Wrong identities %v (calls %v)", identities, calls)
+	}
+	statuses, err := cfg.GetIdentityVerificationAttributes(context.Background(), identities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses["pending.com"] != "Pending" || statuses["example.com"] != "Success" {
+		t.Errorf("Wrong statuses: %v", statuses)
+	}
+}
+
+// TestVerifiedIdentityCache_Check will test the verified identity checks
+func TestVerifiedIdentityCache_Check(t *testing.T) {
+	calls := make(map[string]int)
+	server := newIdentityServer(calls)
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	cache := NewVerifiedIdentityCache(0)
+	for _, from := range []string{"a@example.com", "a@mail.example.com", "Jane <JANE@gmail.com>"} {
+		if err := cache.Check(context.Background(), cfg, from); err != nil {
+			t.Errorf("%s: %s", from, err)
+		}
+	}
+	for _, from := range []string{"a@pending.com", "john@gmail.com"} {
+		if err := cache.Check(context.Background(), cfg, from); !errors.Is(err, ErrUnverifiedSender) {
+			t.Errorf("%s: expected ErrUnverifiedSender got %v", from, err)
+		}
+	}
+	if calls["GetIdentityVerificationAttributes"] != 1 {
+		t.Errorf("expected a single refresh: %v", calls)
+	}
+
+	cache.Add("region", "pending.com")
+	if err := cache.Check(context.Background(), cfg, "a@pending.com"); err != nil {
+		t.Errorf("expected the added identity to be verified: %s", err)
+	}
+	cache.Remove("region", "example.com")
+	if err := cache.Check(context.Background(), cfg, "a@example.com"); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected the removed identity to be unverified: %v", err)
+	}
+}
+
+// TestVerifiedIdentityCache_Regions will test each region is listed using the sending Config
+func TestVerifiedIdentityCache_Regions(t *testing.T) {
+	calls := make(map[string]int)
+	identities := identityHandler(calls)
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if region := strings.Split(r.Header.Get("Authorization"), "/")[2]; len(regions) == 0 || regions[len(regions)-1] != region {
+			regions = append(regions, region)
+		}
+		identities(w, r)
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "us-east-1", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	other := cfg.Clone(OverrideRegion("eu-west-1"), OverrideEndpoint(server.URL))
+	cache := NewVerifiedIdentityCache(0)
+	cache.Add("eu-west-1", "other.com")
+	for _, c := range []*Config{cfg, other, cfg} {
+		if err := cache.Check(context.Background(), c, "a@example.com"); err != nil {
+			t.Errorf("%s: %s", c.Region, err)
+		}
+	}
+	if len(regions) != 2 || regions[0] != "us-east-1" || regions[1] != "eu-west-1" {
+		t.Errorf("expected a refresh per region: %v", regions)
+	}
+	if err := cache.Check(context.Background(), cfg, "a@other.com"); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected the identity to be verified in eu-west-1 only: %v", err)
+	}
+}
+
+// TestConfig_SendVerifiedIdentities will test that unverified sends are not sent
+func TestConfig_SendVerifiedIdentities(t *testing.T) {
+	calls := make(map[string]int)
+	server := newIdentityServer(calls)
+	defer server.Close()
+
+	cfg := &Config{Endpoint: server.URL, Region: "region", AccessKeyID: "a", SecretAccessKey: "s", HTTPClient: http.DefaultClient}
+	cfg.VerifiedIdentities = NewVerifiedIdentityCache(0)
+	if _, err := cfg.Send(context.Background(), &Message{From: "a@other.com", To: []string{to}}); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected ErrUnverifiedSender got %v", err)
+	}
+	if _, err := cfg.SendRaw(context.Background(), &RawMessage{Data: []byte("From: a@other.com\r\n\r\nbody")}); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected ErrUnverifiedSender got %v", err)
+	}
+	if _, err := cfg.Send(context.Background(), &Message{From: "a@example.com", To: []string{to}}); err != nil {
+		t.Fatal(err)
+	}
+	if calls["SendEmail"] != 1 {
+		t.Errorf("expected a single send: %v", calls)
+	}
+
+	// Checked in the region of the call
+	if _, err := cfg.Send(context.Background(), &Message{From: "a@example.com", To: []string{to}}, WithRegion("eu-west-1"), WithEndpoint(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+	if calls["GetIdentityVerificationAttributes"] != 2 {
+		t.Errorf("expected a refresh for the call region: %v", calls)
+	}
+}